@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/emla2805/tfr/spec"
+	"github.com/emla2805/tfr/utils"
+	"github.com/emla2805/tfr/validate"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var checkSpecFormat string
+
+var checkSpecCmd = &cobra.Command{
+	Use:   "check-spec spec.json {file ... | -}",
+	Short: "Find the records a feature spec fails to parse",
+	Long: `Parse every record with a feature spec the way tf.io.parse_example and
+tf.io.parse_sequence_example do, and report the records that would make the
+input pipeline throw: missing features without a default_value, dtype
+mismatches, wrong numbers of values and inconsistent SparseFeature indices.
+The spec is JSON as written by "tfr spec --lang json", where FixedLenFeatures
+may have a "default_value", FixedLenSequenceFeatures "allow_missing" and
+SparseFeatures an "index_key", "value_key" and "size". The exit status is
+non-zero when any record fails.`,
+	Example: `  $ tfr check-spec spec.json data_tfrecord-*`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("requires a spec file")
+		}
+		return rootCmd.Args(cmd, args[1:])
+	},
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		specFile, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		s, err := spec.Read(specFile)
+		specFile.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", args[0], err)
+		}
+
+		inputs, err := openInputs(args[1:])
+		if err != nil {
+			return err
+		}
+		defer closeInputs(inputs)
+
+		checker := validate.NewSpecChecker(s)
+		err = scanRecords(inputs, func(m proto.Message, meta utils.RecordMeta) error {
+			checker.Check(m, fmt.Sprintf("%s:%d", meta.File, meta.Index))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		anomalies := checker.Anomalies()
+		switch checkSpecFormat {
+		case "text":
+			err = validate.WriteText(os.Stdout, anomalies, checker.Records())
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(anomalies)
+		default:
+			err = fmt.Errorf("unknown format %q", checkSpecFormat)
+		}
+		if err != nil {
+			return err
+		}
+		if len(anomalies) > 0 {
+			return errors.New("records fail to parse")
+		}
+		return nil
+	},
+}
+
+func init() {
+	checkSpecCmd.Flags().StringVarP(&checkSpecFormat, "format", "f", "text", "output format { text | json }")
+	rootCmd.AddCommand(checkSpecCmd)
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/emla2805/tfr/diff"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var diffKey string
+var diffTolerance float64
+var diffFormat string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff old new",
+	Short: "Compare the records of two inputs feature by feature",
+	Long: `Walk two inputs, each a file or a directory of files, in lockstep, or match
+their records by the values of a --key feature, and report the records only
+one of them holds and, for records that changed, the features added, removed
+or changed. Features are compared by value rather than by their serialized
+bytes, whose order of map entries is arbitrary, and floats within --tolerance
+are equal. The exit status is non-zero when the inputs differ.`,
+	Example: `  $ tfr diff before.tfrecord after.tfrecord
+  $ tfr diff --key user_id --tolerance 1e-6 before/ after/`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var sources []*recordSource
+		for _, path := range args {
+			paths, err := expandPath(path)
+			if err != nil {
+				return err
+			}
+			inputs, err := openFiles(paths)
+			if err != nil {
+				return err
+			}
+			defer closeInputs(inputs)
+			sources = append(sources, &recordSource{inputs: inputs})
+		}
+
+		var records []*diff.Record
+		counts := map[string]int{}
+		emit := func(r *diff.Record) error {
+			counts[r.Type]++
+			if diffFormat == "json" {
+				if r.Type != diff.Unchanged {
+					records = append(records, r)
+				}
+				return nil
+			}
+			return diff.WriteText(os.Stdout, r)
+		}
+		if diffFormat != "text" && diffFormat != "json" {
+			return fmt.Errorf("unknown format %q", diffFormat)
+		}
+
+		opts := diff.Options{Tolerance: diffTolerance}
+		var err error
+		if diffKey == "" {
+			err = diffLockstep(sources[0], sources[1], opts, emit)
+		} else {
+			err = diffByKey(sources[0], sources[1], opts, emit)
+		}
+		if err != nil {
+			return err
+		}
+
+		if diffFormat == "json" {
+			if records == nil {
+				records = []*diff.Record{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("%d changed, %d added, %d removed, %d unchanged\n",
+				counts[diff.Changed], counts[diff.Added], counts[diff.Removed], counts[diff.Unchanged])
+		}
+		if counts[diff.Changed]+counts[diff.Added]+counts[diff.Removed] > 0 {
+			return errors.New("inputs differ")
+		}
+		return nil
+	},
+}
+
+// compareRecords returns the difference between two matched records.
+func compareRecords(before, after proto.Message, oldRef, newRef, key string, opts diff.Options) *diff.Record {
+	r := &diff.Record{Type: diff.Unchanged, Key: key, Old: oldRef, New: newRef}
+	if r.Changes = diff.Records(before, after, opts); len(r.Changes) > 0 {
+		r.Type = diff.Changed
+	}
+	return r
+}
+
+// diffLockstep compares the old and new records at the same position.
+func diffLockstep(oldSource, newSource *recordSource, opts diff.Options, emit func(*diff.Record) error) error {
+	for {
+		before, oldRef, err := oldSource.next()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		oldDone := err == io.EOF
+		after, newRef, err := newSource.next()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		newDone := err == io.EOF
+
+		switch {
+		case oldDone && newDone:
+			return nil
+		case oldDone:
+			err = emit(&diff.Record{Type: diff.Added, New: newRef})
+		case newDone:
+			err = emit(&diff.Record{Type: diff.Removed, Old: oldRef})
+		default:
+			err = emit(compareRecords(before, after, oldRef, newRef, "", opts))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type keyedRecord struct {
+	m       proto.Message
+	ref     string
+	matched bool
+}
+
+// diffByKey compares the old and new records with the same --key value, in
+// the order of the old records. The new records are held in memory. Records
+// with a key seen more than once are matched in order.
+func diffByKey(oldSource, newSource *recordSource, opts diff.Options, emit func(*diff.Record) error) error {
+	var newRecords []*keyedRecord
+	byKey := map[string][]*keyedRecord{}
+	for {
+		m, ref, err := newSource.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		r := &keyedRecord{m: m, ref: ref}
+		newRecords = append(newRecords, r)
+		if key, ok := diff.Key(m, diffKey); ok {
+			byKey[key] = append(byKey[key], r)
+		}
+	}
+
+	for {
+		m, ref, err := oldSource.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		key, ok := diff.Key(m, diffKey)
+		if !ok || len(byKey[key]) == 0 {
+			if err := emit(&diff.Record{Type: diff.Removed, Key: key, Old: ref}); err != nil {
+				return err
+			}
+			continue
+		}
+		match := byKey[key][0]
+		byKey[key] = byKey[key][1:]
+		match.matched = true
+		if err := emit(compareRecords(m, match.m, ref, match.ref, key, opts)); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range newRecords {
+		if r.matched {
+			continue
+		}
+		key, _ := diff.Key(r.m, diffKey)
+		if err := emit(&diff.Record{Type: diff.Added, Key: key, New: r.ref}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffKey, "key", "k", "", "feature to match records by instead of their position")
+	diffCmd.Flags().Float64Var(&diffTolerance, "tolerance", 0, "largest difference between float values considered equal")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "output format { text | json }")
+	rootCmd.AddCommand(diffCmd)
+}
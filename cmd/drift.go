@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/emla2805/tfr/stats"
+	"github.com/spf13/cobra"
+)
+
+var driftFormat string
+var driftParallel int
+var driftThresholds stats.Thresholds
+
+var driftCmd = &cobra.Command{
+	Use:   "drift baseline target",
+	Short: "Compare the feature distributions of two datasets",
+	Long: `Compute the statistics of a baseline and a target dataset, each a file or a
+directory of files, and compare every feature: bytes features by the
+L-infinity distance of their value frequencies, int64 and float features by
+the Jensen-Shannon divergence of their histograms over the baseline deciles,
+as well as their coverage and kind. The exit status is non-zero when a
+threshold is exceeded or a feature changed kind, which catches training and
+serving skew before it reaches production.`,
+	Example: `  $ tfr drift train/ serving/
+  $ tfr drift --max-linf 0.05 --max-js 0.02 train/ serving/`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var builders []*stats.Builder
+		for _, path := range args {
+			paths, err := expandPath(path)
+			if err != nil {
+				return err
+			}
+			if len(paths) == 0 {
+				return fmt.Errorf("%s: no files found", path)
+			}
+			inputs, err := openFiles(paths)
+			if err != nil {
+				return err
+			}
+			b, err := computeStats(inputs, driftParallel)
+			closeInputs(inputs)
+			if err != nil {
+				return err
+			}
+			builders = append(builders, b)
+		}
+
+		drifts := stats.Drift(builders[0], builders[1], driftThresholds)
+		var err error
+		switch driftFormat {
+		case "text":
+			err = stats.WriteDrift(os.Stdout, drifts)
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(drifts)
+		default:
+			err = fmt.Errorf("unknown format %q", driftFormat)
+		}
+		if err != nil {
+			return err
+		}
+		for _, d := range drifts {
+			if len(d.Anomalies) > 0 {
+				return errors.New("drift detected")
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	driftCmd.Flags().StringVarP(&driftFormat, "format", "f", "text", "output format { text | json }")
+	driftCmd.Flags().IntVarP(&driftParallel, "parallel", "p", runtime.NumCPU(), "number of files, or ranges of large files, to read in parallel")
+	driftCmd.Flags().Float64Var(&driftThresholds.LInfinity, "max-linf", 0.1, "maximum L-infinity distance of bytes features, 0 to disable")
+	driftCmd.Flags().Float64Var(&driftThresholds.JensenShannon, "max-js", 0.1, "maximum Jensen-Shannon divergence of numeric features, 0 to disable")
+	driftCmd.Flags().Float64Var(&driftThresholds.Coverage, "max-coverage-diff", 0.1, "maximum difference in coverage, 0 to disable")
+	rootCmd.AddCommand(driftCmd)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emla2805/tfr/events"
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/tfrecord"
+	"github.com/emla2805/tfr/utils"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var eventsTags []string
+var eventsFormat string
+
+var eventsCmd = &cobra.Command{
+	Use:   "events {logdir | file} ...",
+	Short: "List the runs and tags of TensorBoard event files and dump scalars",
+	Long: `Read the tensorflow.Event records of TensorBoard event files, the
+events.out.tfevents.* files found under each logdir, and list every tag of
+every run, a run being a directory holding event files, with the kind of its
+values, the number of values and the steps they span.
+
+With --tag, dump the scalar series of the tags matching the globs or
+/regexps/ instead, one row per step. Both legacy simple_value summaries and
+the rank-0 tensors written by tf.summary.scalar in TensorFlow 2 are read.`,
+	Example: `  $ tfr events logs/
+  $ tfr events --tag loss,accuracy --format csv logs/ > metrics.csv
+  $ tfr events --tag '/^eval\//' --format json logs/train/events.out.tfevents.1600000000.host`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var selectTag func(string) bool
+		if len(eventsTags) > 0 {
+			matcher, err := utils.NewFeatureMatcher(eventsTags, nil)
+			if err != nil {
+				return fmt.Errorf("--tag: %v", err)
+			}
+			selectTag = matcher.Match
+		}
+
+		index := events.NewIndex(selectTag)
+		for _, arg := range args {
+			files, err := eventFiles(arg)
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				if err := readEvents(f.path, f.run, index); err != nil {
+					return err
+				}
+			}
+		}
+
+		if selectTag == nil {
+			return writeTags(os.Stdout, index.Tags())
+		}
+		return writePoints(os.Stdout, index.Points())
+	},
+}
+
+type eventFile struct {
+	path, run string
+}
+
+// eventFiles returns the event files under a logdir, in lexical order, with
+// their run: the directory holding them relative to the logdir. A file
+// given by itself belongs to the run of its directory.
+func eventFiles(path string) ([]eventFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []eventFile{{path: path, run: filepath.Dir(path)}}, nil
+	}
+	var files []eventFile
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.Contains(info.Name(), "tfevents") {
+			return nil
+		}
+		run, err := filepath.Rel(path, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		files = append(files, eventFile{path: p, run: filepath.ToSlash(run)})
+		return nil
+	})
+	if err == nil && len(files) == 0 {
+		err = fmt.Errorf("%s: no event files found", path)
+	}
+	return files, err
+}
+
+// readEvents adds the events of the file at path to index.
+func readEvents(path, run string, index *events.Index) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := tfrecord.NewReader(f, readOptions)
+	event := &protobuf.Event{}
+	for {
+		data, err := reader.Next()
+		// The event files of running jobs may end with a partial record.
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		if err := proto.Unmarshal(data, event); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		index.Add(run, event)
+	}
+}
+
+func writeTags(w io.Writer, tags []*events.Tag) error {
+	switch eventsFormat {
+	case "text":
+		return events.WriteTags(w, tags)
+	case "csv":
+		return events.WriteTagsCSV(w, tags)
+	case "json":
+		return writeJSON(w, tags)
+	}
+	return fmt.Errorf("unknown format %q", eventsFormat)
+}
+
+func writePoints(w io.Writer, points []events.Point) error {
+	switch eventsFormat {
+	case "text":
+		return events.WritePoints(w, points)
+	case "csv":
+		return events.WritePointsCSV(w, points)
+	case "json":
+		if points == nil {
+			points = []events.Point{}
+		}
+		return writeJSON(w, points)
+	}
+	return fmt.Errorf("unknown format %q", eventsFormat)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func init() {
+	eventsCmd.Flags().StringSliceVarP(&eventsTags, "tag", "t", nil, "dump the scalars of tags matching these globs or /regexps/")
+	eventsCmd.Flags().StringVarP(&eventsFormat, "format", "f", "text", "output format { text | csv | json }")
+	rootCmd.AddCommand(eventsCmd)
+}
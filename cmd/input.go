@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emla2805/tfr/storage"
+	"github.com/emla2805/tfr/tfrecord"
+	"github.com/emla2805/tfr/utils"
+)
+
+// input is a named stream of TFRecords.
+type input struct {
+	name string
+	r    io.ReadCloser
+	// record is the type of the records, detected with --record auto.
+	record string
+}
+
+// detectRecords is the number of records --record auto inspects.
+const detectRecords = 10
+
+// newInput returns an input reading r, detecting its record type from the
+// first records with --record auto.
+func newInput(name string, r io.ReadCloser) (input, error) {
+	in := input{name: name, r: r, record: record}
+	if record != recordAuto || messageType != nil {
+		return in, nil
+	}
+
+	// Seekable inputs are read again from where detection started, so that
+	// --skip can still seek them. Others replay the records read for
+	// detection before the rest of r.
+	seeker, _ := r.(io.Seeker)
+	start := int64(-1)
+	if seeker != nil {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			start = offset
+		}
+	}
+	var buf bytes.Buffer
+	reader := tfrecord.NewReader(io.TeeReader(r, &buf), tfrecord.Options{IgnoreChecksums: true})
+	var records [][]byte
+	for len(records) < detectRecords {
+		data, err := reader.Next()
+		if err != nil {
+			// Errors are reported when the records are read again.
+			break
+		}
+		records = append(records, data)
+	}
+	if start < 0 {
+		in.r = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(&buf, r), r}
+	} else if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return in, fmt.Errorf("%s: %v", name, err)
+	}
+
+	var err error
+	if in.record, err = utils.DetectRecordType(records); err != nil {
+		return in, fmt.Errorf("%s: cannot detect record type: %v", name, err)
+	}
+	return in, nil
+}
+
+// openInputs opens every path in args, expanding the globs of remote ones.
+// Stdin is read first when it is a pipe, or wherever "-" is given.
+func openInputs(args []string) ([]input, error) {
+	var paths []string
+	if isInputFromPipe() && !contains(args, "-") {
+		paths = append(paths, "-")
+	}
+	for _, arg := range args {
+		if !storage.IsRemote(arg) || !storage.HasMeta(arg) {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := storage.Glob(inputContext(), arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%s: no files found", arg)
+		}
+		paths = append(paths, matches...)
+	}
+	return openFiles(paths)
+}
+
+// inputContext is the context files are opened in, which is done once the
+// command is interrupted.
+func inputContext() context.Context {
+	if readOptions.Context != nil {
+		return readOptions.Context
+	}
+	return context.Background()
+}
+
+// openFiles opens every path, with "-" for stdin, local paths and gs://,
+// s3://, http:// and https:// URLs. Files ending with .gz are decompressed.
+func openFiles(paths []string) ([]input, error) {
+	var inputs []input
+	for _, path := range paths {
+		var r io.ReadCloser = os.Stdin
+		if path != "-" {
+			file, err := storage.Open(inputContext(), path)
+			if err == nil && strings.HasSuffix(path, ".gz") {
+				file, err = gunzip(file)
+			}
+			if err != nil {
+				closeInputs(inputs)
+				return nil, err
+			}
+			r = file
+		}
+		in, err := newInput(path, r)
+		if err != nil {
+			r.Close()
+			closeInputs(inputs)
+			return nil, err
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// gunzip returns a reader of the decompressed contents of r, closing r when
+// closed.
+func gunzip(r io.ReadCloser) (io.ReadCloser, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{zr, r}, nil
+}
+
+func closeInputs(inputs []input) {
+	for _, in := range inputs {
+		in.r.Close()
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPath lists the files of a directory, skipping hidden files, or
+// returns path itself when it is not a directory. Remote paths are
+// directories when they end with a slash, and globs are expanded.
+func expandPath(path string) ([]string, error) {
+	if storage.IsRemote(path) {
+		return expandRemote(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || strings.HasPrefix(entry.Name(), "_") {
+			continue
+		}
+		paths = append(paths, filepath.Join(path, entry.Name()))
+	}
+	return paths, nil
+}
+
+// expandRemote lists the files directly under a remote path ending with a
+// slash, skipping hidden files, expands a glob or returns path itself.
+func expandRemote(path string) ([]string, error) {
+	if storage.HasMeta(path) {
+		return storage.Glob(inputContext(), path)
+	}
+	if !strings.HasSuffix(path, "/") {
+		return []string{path}, nil
+	}
+	fsys, err := storage.Lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fsys.List(inputContext(), path)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, f := range files {
+		name := strings.TrimPrefix(f.Name, path)
+		if strings.Contains(name, "/") || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			continue
+		}
+		paths = append(paths, f.Name)
+	}
+	return paths, nil
+}
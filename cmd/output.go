@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/emla2805/tfr/tfrecord"
+	"github.com/emla2805/tfr/utils"
+	"golang.org/x/term"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordWriter writes decoded records in one of the output formats.
+type recordWriter interface {
+	Write(m proto.Message, meta utils.RecordMeta) error
+	// Flush writes out anything still buffered once all records are read.
+	Flush() error
+}
+
+func newRecordWriter(w io.Writer, format string) (recordWriter, error) {
+	if (len(decodeTensors) > 0 || len(nestedExamples) > 0 || jsonArray) && (format != "json" || templateText != "" || templateFile != "") {
+		return nil, errors.New("--decode-tensors, --nested-example and --json-array only apply to the json format")
+	}
+	if templateText != "" || templateFile != "" {
+		return newTemplateRecordWriter(w)
+	}
+	switch format {
+	case "json":
+		opts, err := marshalOptions()
+		if err != nil {
+			return nil, err
+		}
+		enc := utils.NewEncoder(w, opts)
+		if jsonArray {
+			enc.SetFraming(utils.JSONArray)
+		}
+		// Show records as they are read on a terminal.
+		if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			enc.SetBufferSize(0)
+		}
+		return &jsonRecordWriter{enc: enc, withMeta: withMeta}, nil
+	case "table":
+		return newTableRecordWriter(w), nil
+	case "tfrecord":
+		if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			return nil, errors.New("refusing to write TFRecords to a terminal")
+		}
+		return &tfrecordRecordWriter{w: bufio.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+type jsonRecordWriter struct {
+	enc      *utils.Encoder
+	withMeta bool
+}
+
+func (j *jsonRecordWriter) Write(m proto.Message, meta utils.RecordMeta) error {
+	if j.withMeta {
+		return j.enc.EncodeWithMeta(m, meta)
+	}
+	return j.enc.Encode(m)
+}
+
+func (j *jsonRecordWriter) Flush() error {
+	return j.enc.Close()
+}
+
+// marshalOptions returns the options decoding the tensors of the features
+// given with --decode-tensors, detecting them with auto, and the Examples of
+// those given with --nested-example, resolving the types of --descriptor-set.
+func marshalOptions() (utils.MarshalOptions, error) {
+	opts := utils.MarshalOptions{Resolver: typeResolver}
+	var patterns []string
+	for _, p := range decodeTensors {
+		if p == "auto" {
+			opts.DetectTensors = true
+		} else {
+			patterns = append(patterns, p)
+		}
+	}
+	if len(patterns) > 0 {
+		matcher, err := utils.NewFeatureMatcher(patterns, nil)
+		if err != nil {
+			return opts, fmt.Errorf("--decode-tensors: %v", err)
+		}
+		opts.DecodeTensors = matcher.Match
+	}
+	if len(nestedExamples) > 0 {
+		matcher, err := utils.NewFeatureMatcher(nestedExamples, nil)
+		if err != nil {
+			return opts, fmt.Errorf("--nested-example: %v", err)
+		}
+		opts.NestedExamples = matcher.Match
+	}
+	return opts, nil
+}
+
+// tfrecordRecordWriter re-encodes records as TFRecords, so that filtered or
+// projected records can be written back to a file.
+type tfrecordRecordWriter struct {
+	w *bufio.Writer
+}
+
+func (t *tfrecordRecordWriter) Write(m proto.Message, meta utils.RecordMeta) error {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return tfrecord.Write(t.w, data)
+}
+
+func (t *tfrecordRecordWriter) Flush() error {
+	return t.w.Flush()
+}
+
+// tableRecordWriter buffers all records since column widths depend on every
+// row, labeling them by their index in their file, prefixed with its name
+// when they come from several files. Transposed records are written as they
+// come, one block each.
+type tableRecordWriter struct {
+	w         io.Writer
+	transpose bool
+	opts      utils.TableOptions
+	records   []proto.Message
+	metas     []utils.RecordMeta
+}
+
+func newTableRecordWriter(w io.Writer) *tableRecordWriter {
+	t := &tableRecordWriter{w: w, transpose: transpose, opts: utils.TableOptions{Width: terminalWidth()}}
+	if transpose {
+		t.opts.MaxItems = 10
+	}
+	return t
+}
+
+func (t *tableRecordWriter) Write(m proto.Message, meta utils.RecordMeta) error {
+	if t.transpose {
+		if len(t.metas) > 0 {
+			if _, err := fmt.Fprintln(t.w); err != nil {
+				return err
+			}
+		}
+		t.metas = append(t.metas[:0], meta)
+		title := fmt.Sprintf("record %d", meta.Index)
+		if meta.File != "-" {
+			title += " (" + meta.File + ")"
+		}
+		return utils.WriteRecordBlock(t.w, m, title, t.opts)
+	}
+	t.records = append(t.records, proto.Clone(m))
+	t.metas = append(t.metas, meta)
+	return nil
+}
+
+func (t *tableRecordWriter) Flush() error {
+	if t.transpose {
+		return nil
+	}
+	files := map[string]bool{}
+	for _, meta := range t.metas {
+		files[meta.File] = true
+	}
+	labels := make([]string, len(t.metas))
+	for i, meta := range t.metas {
+		labels[i] = strconv.Itoa(meta.Index)
+		if len(files) > 1 {
+			labels[i] = meta.File + ":" + labels[i]
+		}
+	}
+	return utils.WriteLabeledTable(t.w, t.records, labels, t.opts)
+}
+
+// templateRecordWriter renders each record through a user supplied
+// text/template.
+type templateRecordWriter struct {
+	w       io.Writer
+	tmpl    *template.Template
+	newline bool
+	index   int
+}
+
+func newTemplateRecordWriter(w io.Writer) (*templateRecordWriter, error) {
+	if templateText != "" && templateFile != "" {
+		return nil, errors.New("--template and --template-file are mutually exclusive")
+	}
+	text, name := templateText, "template"
+	if templateFile != "" {
+		b, err := ioutil.ReadFile(templateFile)
+		if err != nil {
+			return nil, err
+		}
+		text, name = string(b), templateFile
+	}
+	tmpl, err := template.New(name).Funcs(utils.TemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	// Terminate each record with a newline unless the template already
+	// ends with one, as template files usually do.
+	return &templateRecordWriter{w: w, tmpl: tmpl, newline: !strings.HasSuffix(text, "\n")}, nil
+}
+
+func (t *templateRecordWriter) Write(m proto.Message, meta utils.RecordMeta) error {
+	data := utils.TemplateData(m, t.index)
+	data["Meta"] = meta
+	if err := t.tmpl.Execute(t.w, data); err != nil {
+		return err
+	}
+	t.index++
+	if t.newline {
+		_, err := fmt.Fprintln(t.w)
+		return err
+	}
+	return nil
+}
+
+func (t *templateRecordWriter) Flush() error {
+	return nil
+}
+
+// terminalWidth returns the width of the terminal on stdout, falling back to
+// $COLUMNS, or 0 when output is not going to a terminal.
+func terminalWidth() int {
+	if fd := int(os.Stdout.Fd()); term.IsTerminal(fd) {
+		if width, _, err := term.GetSize(fd); err == nil && width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 0
+}
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/tfrecord"
+	"github.com/emla2805/tfr/utils"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// recordAuto is the --record value detecting the record type of each input.
+const recordAuto = "auto"
+
+// recordELWC is the --record value for TF-Ranking's ExampleListWithContext.
+const recordELWC = "elwc"
+
+// checkRecordType rejects unknown --record values.
+func checkRecordType() error {
+	switch record {
+	case utils.RecordExample, utils.RecordSequenceExample, recordAuto, recordELWC:
+		return nil
+	}
+	return fmt.Errorf("unknown record type %q, expected %s, %s, %s or %s",
+		record, utils.RecordExample, utils.RecordSequenceExample, recordELWC, recordAuto)
+}
+
+// messageType is the type of records other than Examples and
+// SequenceExamples, loaded from --descriptor-set or given by --record elwc.
+var messageType pref.MessageType
+
+// typeResolver resolves the types of google.protobuf.Any messages in
+// records of messageType, including those of --descriptor-set.
+var typeResolver utils.TypeResolver
+
+// loadMessageType loads --message from --descriptor-set, or sets the type of
+// --record elwc. Such records are only written as JSON or TFRecords by the
+// root command.
+func loadMessageType(cmd *cobra.Command) error {
+	switch {
+	case descriptorSet != "" || messageName != "":
+		if descriptorSet == "" || messageName == "" {
+			return errors.New("--descriptor-set and --message must be given together")
+		}
+		if record == recordELWC {
+			return errors.New("--message and --record elwc are mutually exclusive")
+		}
+		var err error
+		if messageType, typeResolver, err = utils.LoadMessageType(descriptorSet, messageName); err != nil {
+			return err
+		}
+	case record == recordELWC:
+		messageType = (&protobuf.ExampleListWithContext{}).ProtoReflect().Type()
+	default:
+		return nil
+	}
+
+	name := messageType.Descriptor().FullName()
+	if cmd.HasParent() {
+		return fmt.Errorf("%s only reads Examples and SequenceExamples, not %s", cmd.Name(), name)
+	}
+	if templateText != "" || templateFile != "" {
+		return fmt.Errorf("templates do not support %s records", name)
+	}
+	if format != "json" && format != "tfrecord" {
+		return fmt.Errorf("%s records only support the json and tfrecord formats, got %q", name, format)
+	}
+	return nil
+}
+
+// newRecord returns an empty message of the record type of the input.
+func (in input) newRecord() proto.Message {
+	if messageType != nil {
+		return messageType.New().Interface()
+	}
+	if in.record == utils.RecordSequenceExample {
+		return &protobuf.SequenceExample{}
+	}
+	return &protobuf.Example{}
+}
+
+// scanRecords decodes up to --number records of inputs, calling fn with each
+// one. The message is reused for every record of an input.
+func scanRecords(inputs []input, fn func(m proto.Message, meta utils.RecordMeta) error) error {
+	var count int64
+	for _, in := range inputs {
+		m := in.newRecord()
+		err := scanInput(in, m, &count, func(_ []byte, meta utils.RecordMeta) error {
+			return fn(m, meta)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitSize is the size of the ranges that parallel scans split large local
+// files into, so that a single file is read on several cores.
+const splitSize = 64 << 20
+
+// scanUnit is the part of an input read by one goroutine of a parallel scan:
+// the whole input, or a range of records of a memory-mapped file.
+type scanUnit struct {
+	in   input
+	file *tfrecord.MappedFile
+	r    tfrecord.Range
+}
+
+// reader returns a reader of the records of u.
+func (u scanUnit) reader() recordReader {
+	if u.file == nil {
+		return tfrecord.NewReader(u.in.r, readOptions)
+	}
+	return u.file.RangeReader(u.r, readOptions)
+}
+
+// splitInputs splits the local files of inputs larger than splitSize into up
+// to workers ranges of records each, memory-mapping them. Remote, compressed
+// and unmappable files are read whole, as are all files when --number is
+// given, so that the records read are the first ones. The mapped files are
+// closed by closeUnits.
+func splitInputs(inputs []input, workers int) []scanUnit {
+	var units []scanUnit
+	for _, in := range inputs {
+		n := 1
+		if info, err := os.Stat(in.name); err == nil && in.name != "-" && info.Mode().IsRegular() &&
+			!strings.HasSuffix(in.name, ".gz") && numberRecords == math.MaxInt32 {
+			n = int(min(int64(workers), (info.Size()+splitSize-1)/splitSize))
+		}
+		if n > 1 {
+			if file, err := tfrecord.Mmap(in.name); err == nil {
+				for _, r := range file.Split(n) {
+					units = append(units, scanUnit{in: in, file: file, r: r})
+				}
+				continue
+			}
+		}
+		units = append(units, scanUnit{in: in})
+	}
+	return units
+}
+
+// closeUnits unmaps the files mapped by splitInputs.
+func closeUnits(units []scanUnit) {
+	for i, u := range units {
+		if u.file != nil && (i == 0 || units[i-1].file != u.file) {
+			u.file.Close()
+		}
+	}
+}
+
+// scanParallel scans up to workers units at a time, decoding up to --number
+// records in total. Each unit gets a message of its own, and fn is called
+// with the index of the unit. Which records make up the --number read
+// depends on scheduling. With lazy, Examples are not decoded: fn gets a nil
+// message and the serialized record, for an ExampleView.
+func scanParallel(units []scanUnit, workers int, lazy bool, fn func(i int, m proto.Message, data []byte, meta utils.RecordMeta) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	var count int64
+	errs := make([]error, len(units))
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, u := range units {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, u scanUnit) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			m := u.in.newRecord()
+			if _, ok := m.(*protobuf.Example); ok && lazy {
+				m = nil
+			}
+			errs[i] = scanReader(u.in, u.reader(), m, &count, func(data []byte, meta utils.RecordMeta) error {
+				return fn(i, m, data, meta)
+			})
+		}(i, u)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readOptions are the options of the readers of inputs, which stop when
+// the command is interrupted.
+var readOptions tfrecord.Options
+
+// recordReader reads the records of an input: a tfrecord.Reader, or a
+// tfrecord.SliceReader of a memory-mapped file.
+type recordReader interface {
+	Next() ([]byte, error)
+	Index() int
+	Offset() int64
+	ChecksumOK() bool
+}
+
+// recordMeta describes the last record read by r from in.
+func recordMeta(in input, r recordReader, data []byte) utils.RecordMeta {
+	return utils.RecordMeta{
+		File:   in.name,
+		Index:  r.Index(),
+		Offset: r.Offset(),
+		Length: len(data),
+		CRCOK:  r.ChecksumOK(),
+	}
+}
+
+// scanInput decodes the records of in into m, unless it is nil, calling fn
+// after each one with the serialized record, until count, shared by all
+// inputs, reaches --number.
+func scanInput(in input, m proto.Message, count *int64, fn func(data []byte, meta utils.RecordMeta) error) error {
+	return scanReader(in, tfrecord.NewReader(in.r, readOptions), m, count, fn)
+}
+
+// scanReader is scanInput reading the records of in with reader.
+func scanReader(in input, reader recordReader, m proto.Message, count *int64, fn func(data []byte, meta utils.RecordMeta) error) error {
+	for atomic.LoadInt64(count) < int64(numberRecords) {
+		data, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", in.name, err)
+		}
+		if atomic.AddInt64(count, 1) > int64(numberRecords) {
+			return nil
+		}
+		if m != nil {
+			if err := proto.Unmarshal(data, m); err != nil {
+				return fmt.Errorf("%s: %v", in.name, err)
+			}
+		}
+		if err := fn(data, recordMeta(in, reader, data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordSource reads the records of inputs one at a time, up to --number.
+type recordSource struct {
+	inputs []input
+	reader *tfrecord.Reader
+	count  int
+}
+
+// next decodes the next record and returns it with its file:index, or
+// io.EOF after the last record.
+func (s *recordSource) next() (proto.Message, string, error) {
+	for len(s.inputs) > 0 && s.count < numberRecords {
+		in := s.inputs[0]
+		if s.reader == nil {
+			s.reader = tfrecord.NewReader(in.r, readOptions)
+		}
+		data, err := s.reader.Next()
+		if err == io.EOF {
+			s.inputs = s.inputs[1:]
+			s.reader = nil
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %v", in.name, err)
+		}
+		m := in.newRecord()
+		if err := proto.Unmarshal(data, m); err != nil {
+			return nil, "", fmt.Errorf("%s: %v", in.name, err)
+		}
+		s.count++
+		return m, fmt.Sprintf("%s:%d", in.name, s.reader.Index()), nil
+	}
+	return nil, "", io.EOF
+}
@@ -16,7 +16,7 @@ limitations under the License.
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
@@ -24,13 +24,30 @@ import (
 	"io"
 	"math"
 	"os"
+	"os/signal"
 
+	"github.com/emla2805/tfr/filter"
 	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/tfrecord"
 	"github.com/emla2805/tfr/utils"
 )
 
 var numberRecords int
+var skipRecords int
 var record string
+var format string
+var transpose bool
+var templateText string
+var templateFile string
+var withMeta bool
+var features []string
+var excludeFeatures []string
+var where string
+var descriptorSet string
+var messageName string
+var decodeTensors []string
+var nestedExamples []string
+var jsonArray bool
 
 var rootCmd = &cobra.Command{
 	Use:   "tfr {file ... | -}",
@@ -58,6 +75,13 @@ reads serialized .tfrecord files and outputs results as JSON on standard output.
       }
     }
   }`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkRecordType(); err != nil {
+			return err
+		}
+		readOptions.Context = cmd.Context()
+		return loadMessageType(cmd)
+	},
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 0 || isInputFromPipe() {
 			return nil
@@ -65,66 +89,192 @@ reads serialized .tfrecord files and outputs results as JSON on standard output.
 		return errors.New("requires argument or stdin")
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var readers []io.Reader
-		if isInputFromPipe() {
-			readers = append(readers, os.Stdin)
+		inputs, err := openInputs(args)
+		if err != nil {
+			return err
 		}
+		defer closeInputs(inputs)
 
-		for _, path := range args {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-			readers = append(readers, file)
+		p, err := newRecordPrinter(os.Stdout)
+		if err != nil {
+			return err
 		}
-		multi := io.MultiReader(readers...)
-
-		scanner := bufio.NewScanner(multi)
-		scanner.Split(utils.ScanTFRecord)
-
-		var example proto.Message
-		switch record {
-		case "example":
-			example = &protobuf.Example{}
-		case "sequence_example":
-			example = &protobuf.SequenceExample{}
-		default:
-			example = &protobuf.Example{}
+		// Write out the records printed before any error.
+		err = p.print(inputs)
+		if flushErr := p.writer.Flush(); err == nil {
+			err = flushErr
 		}
+		return err
+	},
+}
+
+// recordPrinter writes the records matching --where, keeping the features
+// selected by --features and --exclude-features, in the output format.
+type recordPrinter struct {
+	matcher *utils.FeatureMatcher
+	// wireMatcher also keeps the features --where needs.
+	wireMatcher *utils.FeatureMatcher
+	predicate   *filter.Expr
+	writer      recordWriter
+	count       int
+	skipped     int
+}
 
-		count := 0
-		for scanner.Scan() {
-			if count >= numberRecords {
+func newRecordPrinter(w io.Writer) (*recordPrinter, error) {
+	p := &recordPrinter{}
+	var err error
+	if len(features) > 0 || len(excludeFeatures) > 0 {
+		if p.matcher, err = utils.NewFeatureMatcher(features, excludeFeatures); err != nil {
+			return nil, err
+		}
+	}
+	if messageType != nil && (p.matcher != nil || where != "") {
+		return nil, fmt.Errorf("--features, --exclude-features and --where do not support %s records", messageType.Descriptor().FullName())
+	}
+
+	p.wireMatcher = p.matcher
+	if where != "" {
+		if p.predicate, err = filter.Compile(where); err != nil {
+			return nil, fmt.Errorf("--where: %v", err)
+		}
+		// Keep the features the filter needs when projecting on the
+		// wire, and prune them once the record has been matched.
+		if p.matcher != nil {
+			p.wireMatcher = p.matcher.WithNames(p.predicate.Features()...)
+		}
+	}
+
+	if p.writer, err = newRecordWriter(w, format); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// print writes the records of inputs after the first --skip ones, up to
+// --number.
+func (p *recordPrinter) print(inputs []input) error {
+	var view utils.ExampleView
+	for _, in := range inputs {
+		example := in.newRecord()
+		project := utils.ProjectExample
+		_, isExample := example.(*protobuf.Example)
+		if _, ok := example.(*protobuf.SequenceExample); ok {
+			project = utils.ProjectSequenceExample
+		}
+		opts := readOptions
+		opts.IgnoreChecksums = withMeta
+		reader := tfrecord.NewReader(in.r, opts)
+		// Skipped records are seeked past, so that their payloads are not
+		// fetched from remote files.
+		if p.skipped < skipRecords {
+			n, err := reader.Skip(skipRecords - p.skipped)
+			p.skipped += n
+			if err == io.EOF {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %v", in.name, err)
+			}
+		}
+		for p.count < numberRecords {
+			raw, err := reader.Next()
+			if err == io.EOF {
 				break
 			}
-			err := proto.Unmarshal(scanner.Bytes(), example)
 			if err != nil {
-				return err
+				return fmt.Errorf("%s: %v", in.name, err)
 			}
 
-			jsonBytes, err := utils.Marshal(example)
-			if err != nil {
-				return err
+			// Examples are matched on a view of the record, so that only
+			// the features of the filter are decoded, and only the records
+			// it matches are decoded in full.
+			matched := false
+			if p.predicate != nil && isExample {
+				ok, err := p.matchView(&view, raw)
+				if err != nil {
+					return fmt.Errorf("%s: record %d: %v", in.name, reader.Index(), err)
+				}
+				if !ok {
+					continue
+				}
+				matched = true
+			}
+
+			data := raw
+			wireMatcher := p.wireMatcher
+			if matched {
+				wireMatcher = p.matcher
+			}
+			if wireMatcher != nil {
+				if data, err = project(data, wireMatcher); err != nil {
+					return fmt.Errorf("%s: %v", in.name, err)
+				}
+			}
+			if err := proto.Unmarshal(data, example); err != nil {
+				return fmt.Errorf("%s: %v", in.name, err)
+			}
+			if p.predicate != nil && !matched {
+				ok, err := p.predicate.Match(utils.FlattenRecord(example))
+				if err != nil {
+					return fmt.Errorf("%s: record %d: %v", in.name, reader.Index(), err)
+				}
+				if !ok {
+					continue
+				}
+				if p.matcher != nil {
+					utils.PruneFeatures(example, p.matcher)
+				}
 			}
-			fmt.Fprintln(os.Stdout, string(jsonBytes))
 
-			count++
+			if err := p.writer.Write(example, recordMeta(in, reader, raw)); err != nil {
+				return err
+			}
+			p.count++
 		}
-		return nil
-	},
+	}
+	return nil
+}
+
+// matchView evaluates --where on the features of the serialized Example
+// data, viewed through view.
+func (p *recordPrinter) matchView(view *utils.ExampleView, data []byte) (bool, error) {
+	if err := view.Reset(data); err != nil {
+		return false, err
+	}
+	fields, err := view.Flatten(p.predicate.Features())
+	if err != nil {
+		return false, err
+	}
+	return p.predicate.Match(fields)
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Stop reading on interrupt, so that buffered output is still written.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
 func init() {
-	rootCmd.Flags().IntVarP(&numberRecords, "number", "n", math.MaxInt32, "number of records to show")
-	rootCmd.Flags().StringVarP(&record, "record", "r", "example", "record type { example | sequence_example }")
+	rootCmd.PersistentFlags().IntVarP(&numberRecords, "number", "n", math.MaxInt32, "number of records to read")
+	rootCmd.Flags().IntVar(&skipRecords, "skip", 0, "number of records to skip before reading, seeking past them")
+	rootCmd.PersistentFlags().StringVarP(&record, "record", "r", utils.RecordExample, "record type { example | sequence_example | elwc | auto }, auto detects it per file")
+	rootCmd.Flags().StringVarP(&format, "format", "f", "json", "output format { json | table | tfrecord }")
+	rootCmd.Flags().BoolVar(&transpose, "transpose", false, "with --format table, show one block of feature/value rows per record")
+	rootCmd.Flags().StringVar(&templateText, "template", "", "render each record with a Go text/template, e.g. '{{index .Features.age 0}}'")
+	rootCmd.Flags().StringSliceVar(&features, "features", nil, "only output features matching these globs or /regexps/")
+	rootCmd.Flags().StringSliceVar(&excludeFeatures, "exclude-features", nil, "do not output features matching these globs or /regexps/")
+	rootCmd.Flags().StringVarP(&where, "where", "w", "", "only output records matching an expression, e.g. 'label == 1 && len(movie) > 2'")
+	rootCmd.Flags().BoolVar(&withMeta, "with-meta", false, "wrap each JSON record with its file, index, offset, length and crc status")
+	rootCmd.Flags().BoolVar(&jsonArray, "json-array", false, "write the JSON records as a single array rather than one per line")
+	rootCmd.Flags().StringVar(&templateFile, "template-file", "", "render each record with the Go text/template in file")
+	rootCmd.Flags().StringSliceVar(&decodeTensors, "decode-tensors", nil, "decode the bytes values of features matching these globs or /regexps/ as serialized tensors, or auto to detect them")
+	rootCmd.Flags().StringSliceVar(&nestedExamples, "nested-example", nil, "decode the bytes values of features matching these globs or /regexps/ as serialized Examples")
+	rootCmd.Flags().StringVar(&descriptorSet, "descriptor-set", "", "FileDescriptorSet holding the --message type of custom records")
+	rootCmd.Flags().StringVar(&messageName, "message", "", "full name of the message type of custom records, e.g. my.pkg.Event")
 }
 
 func isInputFromPipe() bool {
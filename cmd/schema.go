@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/schema"
+	"github.com/emla2805/tfr/utils"
+	"github.com/spf13/cobra"
+)
+
+var schemaFormat string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema {file ... | -}",
+	Short: "Infer a feature schema from records",
+	Long: `Infer the kind, presence and number of values of every feature from the
+records read, or the first --number of them. For sequence examples the
+context features and feature lists are reported separately.`,
+	Example: `  $ tfr schema -n 1000 data_tfrecord-00000-of-00001
+  $ tfr schema --format pbtxt data_tfrecord-* > schema.pbtxt`,
+	Args: rootCmd.Args,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputs, err := openInputs(args)
+		if err != nil {
+			return err
+		}
+		defer closeInputs(inputs)
+
+		builder, err := inferSchema(inputs)
+		if err != nil {
+			return err
+		}
+
+		s := builder.Schema()
+		switch schemaFormat {
+		case "text":
+			return schema.WriteText(os.Stdout, s)
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(s)
+		case "pbtxt":
+			return schema.WritePbtxt(os.Stdout, s)
+		}
+		return fmt.Errorf("unknown format %q", schemaFormat)
+	},
+}
+
+// inferSchema infers the schema of up to --number records of inputs.
+// Examples are read through a view, since only the kinds and numbers of
+// values of their features are needed.
+func inferSchema(inputs []input) (*schema.Builder, error) {
+	builder := schema.NewBuilder()
+	var view utils.ExampleView
+	var count int64
+	for _, in := range inputs {
+		m := in.newRecord()
+		if _, ok := m.(*protobuf.Example); ok {
+			m = nil
+		}
+		err := scanInput(in, m, &count, func(data []byte, meta utils.RecordMeta) error {
+			if m != nil {
+				builder.Add(m)
+				return nil
+			}
+			if err := view.Reset(data); err != nil {
+				return fmt.Errorf("%s: record %d: %v", meta.File, meta.Index, err)
+			}
+			if err := builder.AddView(&view); err != nil {
+				return fmt.Errorf("%s: record %d: %v", meta.File, meta.Index, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return builder, nil
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaFormat, "format", "f", "text", "output format { text | json | pbtxt }")
+	rootCmd.AddCommand(schemaCmd)
+}
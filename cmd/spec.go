@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/emla2805/tfr/spec"
+	"github.com/spf13/cobra"
+)
+
+var specLang string
+var specRagged bool
+
+var specCmd = &cobra.Command{
+	Use:   "spec {file ... | -}",
+	Short: "Generate a tf.io.parse_example feature spec from records",
+	Long: `Generate the feature dict to parse the records with from their inferred
+schema. Features with the same number of values in every record become
+FixedLenFeatures, others VarLenFeatures, or RaggedFeatures with --ragged.
+For sequence examples both the context and sequence feature dicts are
+generated.`,
+	Example: `  $ tfr spec data_tfrecord-00000-of-00001
+  feature_description = {
+      "age": tf.io.FixedLenFeature([], tf.int64),
+      "movie": tf.io.VarLenFeature(tf.string),
+  }`,
+	Args: rootCmd.Args,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputs, err := openInputs(args)
+		if err != nil {
+			return err
+		}
+		defer closeInputs(inputs)
+
+		builder, err := inferSchema(inputs)
+		if err != nil {
+			return err
+		}
+
+		s := builder.Schema()
+		for _, f := range append(s.Features, s.FeatureLists...) {
+			if f.Conflict() {
+				fmt.Fprintf(os.Stderr, "warning: feature %q has conflicting kinds, using %s\n", f.Name, f.Kind)
+			}
+		}
+
+		featureSpec := spec.FromSchema(s, specRagged)
+		switch specLang {
+		case "python":
+			return spec.WritePython(os.Stdout, featureSpec)
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(featureSpec)
+		}
+		return fmt.Errorf("unknown language %q", specLang)
+	},
+}
+
+func init() {
+	specCmd.Flags().StringVarP(&specLang, "lang", "l", "python", "output language { python | json }")
+	specCmd.Flags().BoolVar(&specRagged, "ragged", false, "use RaggedFeature instead of VarLenFeature for variable length features")
+	rootCmd.AddCommand(specCmd)
+}
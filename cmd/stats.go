@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/emla2805/tfr/stats"
+	"github.com/emla2805/tfr/utils"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var statsFormat string
+var statsParallel int
+var statsTop int
+
+var statsCmd = &cobra.Command{
+	Use:   "stats {file ... | -}",
+	Short: "Compute descriptive statistics of every feature",
+	Long: `Compute per-feature statistics in a single pass: the fraction of records
+holding the feature and the distribution of its number of values, the min,
+max, mean, stddev, zeros, NaNs and approximate quantiles of int64 and float
+values, and the most frequent values, an estimate of the number of distinct
+values and the average length of bytes values. Files are read in parallel
+and their statistics merged, and local files larger than 64MB are memory
+mapped and split into ranges read in parallel.`,
+	Example: `  $ tfr stats data_tfrecord-*
+  $ tfr stats --format json data_tfrecord-* > stats.json`,
+	Args: rootCmd.Args,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statsTop < 0 || statsTop > stats.MaxTopValues {
+			return fmt.Errorf("--top must be between 0 and %d", stats.MaxTopValues)
+		}
+		inputs, err := openInputs(args)
+		if err != nil {
+			return err
+		}
+		defer closeInputs(inputs)
+
+		s, err := computeStats(inputs, statsParallel)
+		if err != nil {
+			return err
+		}
+		s.TopValues = statsTop
+		switch statsFormat {
+		case "text":
+			return stats.WriteText(os.Stdout, s.Stats())
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(s.Stats())
+		}
+		return fmt.Errorf("unknown format %q", statsFormat)
+	},
+}
+
+// computeStats computes the statistics of inputs with a Builder per input,
+// or per range of the large files split across workers, merged in the order
+// of the inputs. Examples are read through a view.
+func computeStats(inputs []input, workers int) (*stats.Builder, error) {
+	units := splitInputs(inputs, workers)
+	defer closeUnits(units)
+	builders := make([]*stats.Builder, len(units))
+	views := make([]utils.ExampleView, len(units))
+	for i := range builders {
+		builders[i] = stats.NewBuilder()
+	}
+	err := scanParallel(units, workers, true, func(i int, m proto.Message, data []byte, meta utils.RecordMeta) error {
+		if m != nil {
+			builders[i].Add(m)
+			return nil
+		}
+		// The index of records is only known within a range, so errors
+		// give their offset.
+		if err := views[i].Reset(data); err != nil {
+			return fmt.Errorf("%s: record at offset %d: %v", meta.File, meta.Offset, err)
+		}
+		if err := builders[i].AddView(&views[i]); err != nil {
+			return fmt.Errorf("%s: record at offset %d: %v", meta.File, meta.Offset, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := stats.NewBuilder()
+	for _, b := range builders {
+		s.Merge(b)
+	}
+	return s, nil
+}
+
+func init() {
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", "text", "output format { text | json }")
+	statsCmd.Flags().IntVarP(&statsParallel, "parallel", "p", runtime.NumCPU(), "number of files, or ranges of large files, to read in parallel")
+	statsCmd.Flags().IntVar(&statsTop, "top", stats.DefaultTopValues, "number of most frequent bytes values to report, up to 1000")
+	rootCmd.AddCommand(statsCmd)
+}
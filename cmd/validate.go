@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/emla2805/tfr/schema"
+	"github.com/emla2805/tfr/utils"
+	"github.com/emla2805/tfr/validate"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var validateSchema string
+var validateFormat string
+
+var validateCmd = &cobra.Command{
+	Use:   "validate --schema file {file ... | -}",
+	Short: "Validate records against a schema",
+	Long: `Check every record against a TensorFlow Metadata schema.pbtxt, or a JSON
+schema as written by "tfr schema --format json", for missing or unexpected
+features, wrong kinds, value counts out of range and values outside their
+int, float or string domain. Anomalies are reported per feature with the
+number of records affected and the first of them as file:index. The exit
+status is non-zero when anomalies are found.`,
+	Example:      `  $ tfr validate --schema schema.pbtxt data_tfrecord-*`,
+	Args:         rootCmd.Args,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schemaFile, err := os.Open(validateSchema)
+		if err != nil {
+			return err
+		}
+		s, err := schema.Read(schemaFile)
+		schemaFile.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", validateSchema, err)
+		}
+
+		inputs, err := openInputs(args)
+		if err != nil {
+			return err
+		}
+		defer closeInputs(inputs)
+
+		validator := validate.New(s)
+		err = scanRecords(inputs, func(m proto.Message, meta utils.RecordMeta) error {
+			validator.Validate(m, fmt.Sprintf("%s:%d", meta.File, meta.Index))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		anomalies := validator.Anomalies()
+		switch validateFormat {
+		case "text":
+			err = validate.WriteText(os.Stdout, anomalies, validator.Records())
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(anomalies)
+		default:
+			err = fmt.Errorf("unknown format %q", validateFormat)
+		}
+		if err != nil {
+			return err
+		}
+		if len(anomalies) > 0 {
+			return errors.New("validation failed")
+		}
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&validateSchema, "schema", "s", "", "schema.pbtxt or JSON schema to validate against")
+	validateCmd.Flags().StringVarP(&validateFormat, "format", "f", "text", "output format { text | json }")
+	validateCmd.MarkFlagRequired("schema")
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,190 @@
+// Package diff compares Examples and SequenceExamples feature by feature.
+// Features are compared by value, so the order in which the feature maps
+// were serialized does not matter.
+package diff
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/utils"
+	"google.golang.org/protobuf/proto"
+)
+
+// Change and record types.
+const (
+	Added     = "added"
+	Removed   = "removed"
+	Changed   = "changed"
+	Unchanged = "unchanged"
+)
+
+// Change is a difference in a single feature.
+type Change struct {
+	Feature string `json:"feature"`
+	// FeatureList is set for the feature lists of SequenceExamples.
+	FeatureList bool   `json:"feature_list,omitempty"`
+	Type        string `json:"type"`
+	Old         string `json:"old,omitempty"`
+	New         string `json:"new,omitempty"`
+}
+
+// Options configure how records are compared.
+type Options struct {
+	// Tolerance is the largest absolute difference between float values
+	// considered equal.
+	Tolerance float64
+}
+
+// Records returns the changes from before to after, sorted by feature name
+// with context features before feature lists.
+func Records(before, after proto.Message, opts Options) []Change {
+	if proto.Equal(before, after) {
+		return nil
+	}
+	oldFeatures, oldLists := parts(before)
+	newFeatures, newLists := parts(after)
+
+	changes := compareMaps(utils.FlattenFeatures(oldFeatures), utils.FlattenFeatures(newFeatures), opts)
+	lists := compareMaps(flattenLists(oldLists), flattenLists(newLists), opts)
+	for i := range lists {
+		lists[i].FeatureList = true
+	}
+	return append(changes, lists...)
+}
+
+func parts(m proto.Message) (*protobuf.Features, *protobuf.FeatureLists) {
+	switch m := m.(type) {
+	case *protobuf.Example:
+		return m.GetFeatures(), nil
+	case *protobuf.SequenceExample:
+		return m.GetContext(), m.GetFeatureLists()
+	}
+	return nil, nil
+}
+
+func flattenLists(fl *protobuf.FeatureLists) map[string]interface{} {
+	flat := map[string]interface{}{}
+	for name, steps := range utils.FlattenFeatureLists(fl) {
+		flat[name] = steps
+	}
+	return flat
+}
+
+func compareMaps(before, after map[string]interface{}, opts Options) []Change {
+	var changes []Change
+	for name, o := range before {
+		n, ok := after[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Feature: name, Type: Removed, Old: Format(o)})
+		case !Equal(o, n, opts.Tolerance):
+			changes = append(changes, Change{Feature: name, Type: Changed, Old: Format(o), New: Format(n)})
+		}
+	}
+	for name, n := range after {
+		if _, ok := before[name]; !ok {
+			changes = append(changes, Change{Feature: name, Type: Added, New: Format(n)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Feature < changes[j].Feature })
+	return changes
+}
+
+// Equal reports whether two values returned by utils.FeatureValues, or
+// lists of them for feature lists, are equal. Floats equal within
+// tolerance, and NaN equals NaN.
+func Equal(a, b interface{}, tolerance float64) bool {
+	switch a := a.(type) {
+	case []int64:
+		b, ok := b.([]int64)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	case []float32:
+		b, ok := b.([]float32)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			x, y := float64(a[i]), float64(b[i])
+			if !(x == y || math.Abs(x-y) <= tolerance || math.IsNaN(x) && math.IsNaN(y)) {
+				return false
+			}
+		}
+		return true
+	case []string:
+		b, ok := b.([]string)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !Equal(a[i], b[i], tolerance) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == nil && b == nil
+}
+
+// Format formats a value returned by utils.FeatureValues, or a list of them
+// for feature lists.
+func Format(v interface{}) string {
+	var items []string
+	switch v := v.(type) {
+	case []int64:
+		for _, x := range v {
+			items = append(items, strconv.FormatInt(x, 10))
+		}
+	case []float32:
+		for _, x := range v {
+			items = append(items, strconv.FormatFloat(float64(x), 'g', -1, 32))
+		}
+	case []string:
+		for _, s := range v {
+			items = append(items, strconv.Quote(s))
+		}
+	case []interface{}:
+		for _, step := range v {
+			items = append(items, Format(step))
+		}
+	case nil:
+		return "[]"
+	default:
+		return fmt.Sprint(v)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// Key returns the values of feature in m as a string to match records by,
+// and whether m holds the feature.
+func Key(m proto.Message, feature string) (string, bool) {
+	features, _ := parts(m)
+	f, ok := features.GetFeature()[feature]
+	if !ok {
+		return "", false
+	}
+	return Format(utils.FeatureValues(f)), true
+}
@@ -0,0 +1,52 @@
+package diff
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+)
+
+func TestRecords(t *testing.T) {
+	before := protobuf.NewExample().
+		Int64("age", 29).
+		Strings("movie", "Heat").
+		Float("rating", 9.5, float32(math.NaN())).
+		Strings("user", "u1").
+		Build()
+	after := protobuf.NewExample().
+		Int64("age", 30).
+		Strings("movie", "Heat").
+		Float("rating", 9.5001, float32(math.NaN())).
+		Float("score", 0.5).
+		Build()
+
+	var buf bytes.Buffer
+	err := WriteText(&buf, &Record{Type: Changed, Old: "a:0", New: "b:0", Changes: Records(before, after, Options{Tolerance: 1e-3})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `~ a:0 b:0
+    ~ age: [29] -> [30]
+    + score: [0.5]
+    - user: ["u1"]
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if changes := Records(before, after, Options{}); len(changes) != 4 {
+		t.Errorf("got %d changes without tolerance, want 4", len(changes))
+	}
+}
+
+func TestSequenceRecords(t *testing.T) {
+	before := protobuf.NewSequenceExample().StringSteps("movies", []string{"Heat"}, []string{"Up"}).Build()
+	after := protobuf.NewSequenceExample().StringSteps("movies", []string{"Heat"}).Build()
+	changes := Records(before, after, Options{})
+	want := Change{Feature: "movies", FeatureList: true, Type: Changed, Old: `[["Heat"], ["Up"]]`, New: `[["Heat"]]`}
+	if len(changes) != 1 || changes[0] != want {
+		t.Errorf("got %+v, want %+v", changes, want)
+	}
+}
@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Record is the difference between a record of the old and one of the new
+// input, or a record only one of them holds.
+type Record struct {
+	Type string `json:"type"`
+	// Key is the value of the key feature records were matched by.
+	Key string `json:"key,omitempty"`
+	// Old and New refer to the records as file:index.
+	Old     string   `json:"old,omitempty"`
+	New     string   `json:"new,omitempty"`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+var markers = map[string]string{
+	Added:   "+",
+	Removed: "-",
+	Changed: "~",
+}
+
+// WriteText writes r the way diff(1) marks lines: "+" for added, "-" for
+// removed and "~" for changed records and features. Unchanged records are
+// not written.
+func WriteText(w io.Writer, r *Record) error {
+	if r.Type == Unchanged {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString(markers[r.Type])
+	for _, ref := range []string{r.Old, r.New} {
+		if ref != "" {
+			b.WriteString(" " + ref)
+		}
+	}
+	if r.Key != "" {
+		b.WriteString(" key " + r.Key)
+	}
+	b.WriteString("\n")
+	for _, c := range r.Changes {
+		name := c.Feature
+		if c.FeatureList {
+			name += " (feature list)"
+		}
+		switch c.Type {
+		case Added:
+			fmt.Fprintf(&b, "    + %s: %s\n", name, c.New)
+		case Removed:
+			fmt.Fprintf(&b, "    - %s: %s\n", name, c.Old)
+		case Changed:
+			fmt.Fprintf(&b, "    ~ %s: %s -> %s\n", name, c.Old, c.New)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
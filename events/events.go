@@ -0,0 +1,180 @@
+// Package events reads the summaries of TensorBoard event files, the
+// TFRecords of tensorflow.Event protos written by tf.summary, and extracts
+// their scalar series.
+package events
+
+import (
+	"sort"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/stats"
+	"github.com/emla2805/tfr/utils"
+)
+
+// Kinds of summary values.
+const (
+	Scalar    = "scalar"
+	Histogram = "histogram"
+	Image     = "image"
+	Audio     = "audio"
+	Tensor    = "tensor"
+)
+
+// Point is a value of a scalar series.
+type Point struct {
+	Run      string  `json:"run"`
+	Tag      string  `json:"tag"`
+	Step     int64   `json:"step"`
+	WallTime float64 `json:"wall_time"`
+	// Value is written to JSON as "NaN" or "Infinity" when a run diverged.
+	Value stats.Float `json:"value"`
+}
+
+// Tag summarizes the values of a tag in a run.
+type Tag struct {
+	Run  string `json:"run"`
+	Tag  string `json:"tag"`
+	Kind string `json:"kind"`
+	// Plugin is the TensorBoard plugin the values are meant for, e.g.
+	// scalars, histograms or images, when the writer recorded it.
+	Plugin    string `json:"plugin,omitempty"`
+	Count     int    `json:"count"`
+	FirstStep int64  `json:"first_step"`
+	LastStep  int64  `json:"last_step"`
+}
+
+// ScalarValue returns the value of a legacy simple_value summary, or of a
+// tensor summary holding a single number, and whether v holds one.
+func ScalarValue(v *protobuf.Summary_Value) (float64, bool) {
+	switch value := v.GetValue().(type) {
+	case *protobuf.Summary_Value_SimpleValue:
+		return float64(value.SimpleValue), true
+	case *protobuf.Summary_Value_Tensor:
+		return tensorScalar(value.Tensor)
+	}
+	return 0, false
+}
+
+// tensorScalar returns the number held by a numeric tensor with a single
+// element.
+func tensorScalar(t *protobuf.TensorProto) (float64, bool) {
+	tensor, err := utils.ParseTensor(t)
+	if err != nil {
+		return 0, false
+	}
+	switch values := tensor.Values.(type) {
+	case []float32:
+		if len(values) == 1 {
+			return float64(values[0]), true
+		}
+	case []float64:
+		if len(values) == 1 {
+			return values[0], true
+		}
+	case []int64:
+		if len(values) == 1 {
+			return float64(values[0]), true
+		}
+	case []uint64:
+		if len(values) == 1 {
+			return float64(values[0]), true
+		}
+	}
+	return 0, false
+}
+
+// kind returns the kind of a summary value.
+func kind(v *protobuf.Summary_Value) string {
+	if _, ok := ScalarValue(v); ok {
+		return Scalar
+	}
+	switch v.GetValue().(type) {
+	case *protobuf.Summary_Value_Histo, *protobuf.Summary_Value_ObsoleteOldStyleHistogram:
+		return Histogram
+	case *protobuf.Summary_Value_Image:
+		return Image
+	case *protobuf.Summary_Value_Audio:
+		return Audio
+	}
+	return Tensor
+}
+
+type tagKey struct {
+	run, tag string
+}
+
+// Index collects the tags of the runs of event files and the points of the
+// scalar series selected.
+type Index struct {
+	tags      map[tagKey]*Tag
+	points    []Point
+	selectTag func(tag string) bool
+}
+
+// NewIndex returns an empty Index keeping the points of the tags for which
+// selectTag returns true. A nil selectTag keeps no points.
+func NewIndex(selectTag func(tag string) bool) *Index {
+	return &Index{tags: map[tagKey]*Tag{}, selectTag: selectTag}
+}
+
+// Add adds the summary values of an event of run. Events without a summary,
+// such as the file version or the graph, are skipped.
+func (x *Index) Add(run string, e *protobuf.Event) {
+	for _, v := range e.GetSummary().GetValue() {
+		key := tagKey{run, v.GetTag()}
+		t, ok := x.tags[key]
+		if !ok {
+			t = &Tag{Run: run, Tag: v.GetTag(), Kind: kind(v), FirstStep: e.GetStep(), LastStep: e.GetStep()}
+			x.tags[key] = t
+		}
+		// The metadata is usually only written with the first value.
+		if plugin := v.GetMetadata().GetPluginData().GetPluginName(); plugin != "" {
+			t.Plugin = plugin
+		}
+		t.Count++
+		if e.GetStep() < t.FirstStep {
+			t.FirstStep = e.GetStep()
+		}
+		if e.GetStep() > t.LastStep {
+			t.LastStep = e.GetStep()
+		}
+
+		if x.selectTag == nil || !x.selectTag(v.GetTag()) {
+			continue
+		}
+		if value, ok := ScalarValue(v); ok {
+			x.points = append(x.points, Point{Run: run, Tag: v.GetTag(), Step: e.GetStep(), WallTime: e.GetWallTime(), Value: stats.Float(value)})
+		}
+	}
+}
+
+// Tags returns the tags seen, sorted by run and tag.
+func (x *Index) Tags() []*Tag {
+	tags := make([]*Tag, 0, len(x.tags))
+	for _, t := range x.tags {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Run != tags[j].Run {
+			return tags[i].Run < tags[j].Run
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags
+}
+
+// Points returns the points of the selected scalar series, sorted by run,
+// tag and step. Points of the same step keep the order they were added in.
+func (x *Index) Points() []Point {
+	sort.SliceStable(x.points, func(i, j int) bool {
+		a, b := x.points[i], x.points[j]
+		if a.Run != b.Run {
+			return a.Run < b.Run
+		}
+		if a.Tag != b.Tag {
+			return a.Tag < b.Tag
+		}
+		return a.Step < b.Step
+	})
+	return x.points
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+)
+
+func simpleValue(tag string, v float32) *protobuf.Summary_Value {
+	return &protobuf.Summary_Value{Tag: tag, Value: &protobuf.Summary_Value_SimpleValue{SimpleValue: v}}
+}
+
+func tensorValue(tag string, t *protobuf.TensorProto) *protobuf.Summary_Value {
+	return &protobuf.Summary_Value{Tag: tag, Value: &protobuf.Summary_Value_Tensor{Tensor: t}}
+}
+
+func floatContent(f float32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(f))
+	return b
+}
+
+func summary(step int64, values ...*protobuf.Summary_Value) *protobuf.Event {
+	return &protobuf.Event{
+		WallTime: 1600000000.5 + float64(step),
+		Step:     step,
+		What:     &protobuf.Event_Summary{Summary: &protobuf.Summary{Value: values}},
+	}
+}
+
+var scalarValueTests = []struct {
+	desc  string
+	value *protobuf.Summary_Value
+	want  float64
+	ok    bool
+}{
+	{"simple value", simpleValue("loss", 0.5), 0.5, true},
+	{"float val", tensorValue("loss", &protobuf.TensorProto{
+		Dtype: protobuf.DataType_DT_FLOAT, TensorShape: &protobuf.TensorShapeProto{}, FloatVal: []float32{0.25}}), 0.25, true},
+	{"tensor content", tensorValue("loss", &protobuf.TensorProto{
+		Dtype: protobuf.DataType_DT_FLOAT, TensorShape: &protobuf.TensorShapeProto{}, TensorContent: floatContent(-2)}), -2, true},
+	{"double val", tensorValue("lr", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_DOUBLE, DoubleVal: []float64{1e-3}}), 1e-3, true},
+	{"int64 val", tensorValue("epoch", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_INT64, Int64Val: []int64{3}}), 3, true},
+	{"vector", tensorValue("weights", &protobuf.TensorProto{
+		Dtype:       protobuf.DataType_DT_FLOAT,
+		TensorShape: &protobuf.TensorShapeProto{Dim: []*protobuf.TensorShapeProto_Dim{{Size: 2}}},
+		FloatVal:    []float32{1, 2}}), 0, false},
+	{"string", tensorValue("text", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_STRING, StringVal: [][]byte{[]byte("a")}}), 0, false},
+	{"histogram", &protobuf.Summary_Value{Tag: "h", Value: &protobuf.Summary_Value_Histo{Histo: &protobuf.HistogramProto{}}}, 0, false},
+}
+
+func TestScalarValue(t *testing.T) {
+	for _, tt := range scalarValueTests {
+		got, ok := ScalarValue(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.desc, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	index := NewIndex(func(tag string) bool { return tag == "loss" })
+	index.Add("train", &protobuf.Event{What: &protobuf.Event_FileVersion{FileVersion: "brain.Event:2"}})
+	image := &protobuf.Summary_Value{Tag: "input", Value: &protobuf.Summary_Value_Image{Image: &protobuf.Summary_Image{}},
+		Metadata: &protobuf.SummaryMetadata{PluginData: &protobuf.SummaryMetadata_PluginData{PluginName: "images"}}}
+	index.Add("train", summary(2, simpleValue("loss", 0.5), image))
+	index.Add("train", summary(1, simpleValue("loss", 1)))
+	index.Add("eval", summary(2, simpleValue("loss", 0.75), simpleValue("accuracy", 0.5)))
+
+	wantTags := []*Tag{
+		{Run: "eval", Tag: "accuracy", Kind: Scalar, Count: 1, FirstStep: 2, LastStep: 2},
+		{Run: "eval", Tag: "loss", Kind: Scalar, Count: 1, FirstStep: 2, LastStep: 2},
+		{Run: "train", Tag: "input", Kind: Image, Plugin: "images", Count: 1, FirstStep: 2, LastStep: 2},
+		{Run: "train", Tag: "loss", Kind: Scalar, Count: 2, FirstStep: 1, LastStep: 2},
+	}
+	if got := index.Tags(); !reflect.DeepEqual(got, wantTags) {
+		for _, tag := range got {
+			t.Logf("%+v", tag)
+		}
+		t.Error("unexpected tags")
+	}
+
+	wantPoints := []Point{
+		{Run: "eval", Tag: "loss", Step: 2, WallTime: 1600000002.5, Value: 0.75},
+		{Run: "train", Tag: "loss", Step: 1, WallTime: 1600000001.5, Value: 1},
+		{Run: "train", Tag: "loss", Step: 2, WallTime: 1600000002.5, Value: 0.5},
+	}
+	if got := index.Points(); !reflect.DeepEqual(got, wantPoints) {
+		t.Errorf("got points %+v, want %+v", got, wantPoints)
+	}
+}
+
+func TestPointsJSON(t *testing.T) {
+	index := NewIndex(func(tag string) bool { return true })
+	index.Add("train", summary(1, simpleValue("loss", float32(math.NaN()))))
+	index.Add("train", summary(2, simpleValue("loss", float32(math.Inf(1)))))
+	data, err := json.Marshal(index.Points())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"run":"train","tag":"loss","step":1,"wall_time":1600000001.5,"value":"NaN"},` +
+		`{"run":"train","tag":"loss","step":2,"wall_time":1600000002.5,"value":"Infinity"}]`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// WriteTags writes a table of tags with their kind, number of values and
+// range of steps.
+func WriteTags(w io.Writer, tags []*Tag) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "run\ttag\tkind\tplugin\tvalues\tsteps")
+	for _, t := range tags {
+		plugin := t.Plugin
+		if plugin == "" {
+			plugin = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d-%d\n", t.Run, t.Tag, t.Kind, plugin, t.Count, t.FirstStep, t.LastStep)
+	}
+	return tw.Flush()
+}
+
+// WriteTagsCSV writes the tags as CSV with a header row.
+func WriteTagsCSV(w io.Writer, tags []*Tag) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"run", "tag", "kind", "plugin", "count", "first_step", "last_step"})
+	for _, t := range tags {
+		cw.Write([]string{t.Run, t.Tag, t.Kind, t.Plugin, strconv.Itoa(t.Count),
+			strconv.FormatInt(t.FirstStep, 10), strconv.FormatInt(t.LastStep, 10)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WritePoints writes a table of scalar points.
+func WritePoints(w io.Writer, points []Point) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "run\ttag\tstep\twall_time\tvalue")
+	for _, p := range points {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", p.Run, p.Tag, p.Step, formatFloat(p.WallTime), formatFloat(float64(p.Value)))
+	}
+	return tw.Flush()
+}
+
+// WritePointsCSV writes scalar points as CSV with a header row.
+func WritePointsCSV(w io.Writer, points []Point) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"run", "tag", "step", "wall_time", "value"})
+	for _, p := range points {
+		cw.Write([]string{p.Run, p.Tag, strconv.FormatInt(p.Step, 10), formatFloat(p.WallTime), formatFloat(float64(p.Value))})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
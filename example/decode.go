@@ -0,0 +1,171 @@
+package example
+
+import (
+	"fmt"
+	"reflect"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+)
+
+// Unmarshal stores the features of the Example in the struct v points to.
+// Fields without a feature are left alone. Features of another kind than
+// their field are an error, a *protobuf.KindError, as are values that do not
+// fit their field and several values for a field that is not a slice.
+func Unmarshal(example *protobuf.Example, v interface{}) error {
+	rv, fields, err := unmarshalFields(v)
+	if err != nil {
+		return err
+	}
+	return unmarshalContext(example.GetFeatures(), rv, fields, false)
+}
+
+// UnmarshalSequence stores the context features of the SequenceExample in
+// the fields of the struct v points to, and its feature lists in its
+// slices of structs. The slices get as many elements as the longest of
+// their feature lists has steps.
+func UnmarshalSequence(example *protobuf.SequenceExample, v interface{}) error {
+	rv, fields, err := unmarshalFields(v)
+	if err != nil {
+		return err
+	}
+	if err := unmarshalContext(example.GetContext(), rv, fields, true); err != nil {
+		return err
+	}
+	lists := example.GetFeatureLists().GetFeatureList()
+	for _, f := range fields {
+		if f.steps == nil {
+			continue
+		}
+		n := 0
+		for _, sf := range f.steps {
+			if list, ok := lists[sf.name]; ok && len(list.GetFeature()) > n {
+				n = len(list.GetFeature())
+			}
+		}
+		fv := rv.FieldByIndex(f.index)
+		steps := reflect.MakeSlice(fv.Type(), n, n)
+		for _, sf := range f.steps {
+			for i, feature := range lists[sf.name].GetFeature() {
+				if err := unmarshalFeature(steps.Index(i).FieldByIndex(sf.index), feature, sf); err != nil {
+					return fmt.Errorf("feature list %s step %d: %w", sf.name, i, err)
+				}
+			}
+		}
+		fv.Set(steps)
+	}
+	return nil
+}
+
+func unmarshalFields(v interface{}) (reflect.Value, []*field, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("cannot unmarshal into %T, want a non-nil pointer to a struct", v)
+	}
+	rv = rv.Elem()
+	fields, err := typeFields(rv.Type())
+	return rv, fields, err
+}
+
+// unmarshalContext stores features in the fields of rv that are not steps,
+// which are an error unless sequence is set.
+func unmarshalContext(features *protobuf.Features, rv reflect.Value, fields []*field, sequence bool) error {
+	for _, f := range fields {
+		if f.steps != nil {
+			if !sequence {
+				return fmt.Errorf("field %s holds steps, use UnmarshalSequence", f.name)
+			}
+			continue
+		}
+		feature, ok := features.GetFeature()[f.name]
+		if !ok {
+			continue
+		}
+		if err := unmarshalFeature(rv.FieldByIndex(f.index), feature, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalFeature stores the values of feature in v.
+func unmarshalFeature(v reflect.Value, feature *protobuf.Feature, f *field) error {
+	if kind := feature.KindName(); kind != f.kind {
+		return &protobuf.KindError{Name: f.name, Kind: kind, Want: f.kind}
+	}
+	var n int
+	var set func(e reflect.Value, i int) error
+	switch f.kind {
+	case kindInt64:
+		values := feature.GetInt64List().GetValue()
+		n, set = len(values), func(e reflect.Value, i int) error { return setInt(e, values[i]) }
+	case kindFloat:
+		values := feature.GetFloatList().GetValue()
+		n, set = len(values), func(e reflect.Value, i int) error { e.SetFloat(float64(values[i])); return nil }
+	default:
+		values := feature.GetBytesList().GetValue()
+		n, set = len(values), func(e reflect.Value, i int) error { setBytes(e, values[i]); return nil }
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	switch {
+	case !f.list:
+		if n > 1 {
+			return fmt.Errorf("feature %s: cannot store %d values in %s", f.name, n, v.Type())
+		}
+		if n == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		return wrapValueError(f, set(v, 0))
+	case v.Kind() == reflect.Array:
+		if n != v.Len() {
+			return fmt.Errorf("feature %s: cannot store %d values in %s", f.name, n, v.Type())
+		}
+	default:
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	}
+	for i := 0; i < n; i++ {
+		if err := set(v.Index(i), i); err != nil {
+			return wrapValueError(f, err)
+		}
+	}
+	return nil
+}
+
+func wrapValueError(f *field, err error) error {
+	if err != nil {
+		return fmt.Errorf("feature %s: %v", f.name, err)
+	}
+	return nil
+}
+
+func setInt(v reflect.Value, x int64) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(x != 0)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if x < 0 || v.OverflowUint(uint64(x)) {
+			return fmt.Errorf("value %d overflows %s", x, v.Type())
+		}
+		v.SetUint(uint64(x))
+	default:
+		if v.OverflowInt(x) {
+			return fmt.Errorf("value %d overflows %s", x, v.Type())
+		}
+		v.SetInt(x)
+	}
+	return nil
+}
+
+func setBytes(v reflect.Value, b []byte) {
+	if v.Kind() == reflect.String {
+		v.SetString(string(b))
+		return
+	}
+	v.SetBytes(append([]byte(nil), b...))
+}
@@ -0,0 +1,167 @@
+package example
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+)
+
+// Marshal returns the Example holding the fields of the struct v, or of the
+// struct v points to.
+func Marshal(v interface{}) (*protobuf.Example, error) {
+	rv, fields, err := marshalFields(v)
+	if err != nil {
+		return nil, err
+	}
+	features, err := contextFeatures(rv, fields, false)
+	if err != nil {
+		return nil, err
+	}
+	return &protobuf.Example{Features: features}, nil
+}
+
+// MarshalSequence returns the SequenceExample holding the fields of the
+// struct v, or of the struct v points to. Slices of structs are stored as
+// feature lists and other fields as context features.
+func MarshalSequence(v interface{}) (*protobuf.SequenceExample, error) {
+	rv, fields, err := marshalFields(v)
+	if err != nil {
+		return nil, err
+	}
+	context, err := contextFeatures(rv, fields, true)
+	if err != nil {
+		return nil, err
+	}
+	lists := map[string]*protobuf.FeatureList{}
+	for _, f := range fields {
+		if f.steps == nil {
+			continue
+		}
+		steps := rv.FieldByIndex(f.index)
+		if f.omitEmpty && steps.Len() == 0 {
+			continue
+		}
+		for _, sf := range f.steps {
+			list := &protobuf.FeatureList{Feature: make([]*protobuf.Feature, steps.Len())}
+			for i := range list.Feature {
+				feature, err := marshalFeature(steps.Index(i).FieldByIndex(sf.index), sf)
+				if err != nil {
+					return nil, fmt.Errorf("feature list %s step %d: %v", sf.name, i, err)
+				}
+				list.Feature[i] = feature
+			}
+			lists[sf.name] = list
+		}
+	}
+	return &protobuf.SequenceExample{Context: context, FeatureLists: &protobuf.FeatureLists{FeatureList: lists}}, nil
+}
+
+func marshalFields(v interface{}) (reflect.Value, []*field, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	fields, err := typeFields(rv.Type())
+	return rv, fields, err
+}
+
+// contextFeatures returns the features of the fields of rv that are not
+// steps, which are an error unless sequence is set.
+func contextFeatures(rv reflect.Value, fields []*field, sequence bool) (*protobuf.Features, error) {
+	features := map[string]*protobuf.Feature{}
+	for _, f := range fields {
+		if f.steps != nil {
+			if !sequence {
+				return nil, fmt.Errorf("field %s holds steps, use MarshalSequence", f.name)
+			}
+			continue
+		}
+		fv := rv.FieldByIndex(f.index)
+		if (fv.Kind() == reflect.Ptr && fv.IsNil()) || (f.omitEmpty && isEmpty(fv)) {
+			continue
+		}
+		feature, err := marshalFeature(fv, f)
+		if err != nil {
+			return nil, fmt.Errorf("feature %s: %v", f.name, err)
+		}
+		features[f.name] = feature
+	}
+	return &protobuf.Features{Feature: features}, nil
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// marshalFeature returns the feature holding v, which is empty for nil
+// pointers.
+func marshalFeature(v reflect.Value, f *field) (*protobuf.Feature, error) {
+	values := v
+	if v.Kind() == reflect.Ptr {
+		values = v.Elem()
+	}
+	n := 1
+	if f.list {
+		n = values.Len()
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		n = 0
+	}
+	elem := func(i int) reflect.Value {
+		if f.list {
+			return values.Index(i)
+		}
+		return values
+	}
+
+	switch f.kind {
+	case kindInt64:
+		ints := make([]int64, n)
+		for i := range ints {
+			x, err := intValue(elem(i))
+			if err != nil {
+				return nil, err
+			}
+			ints[i] = x
+		}
+		return protobuf.Int64Feature(ints...), nil
+	case kindFloat:
+		floats := make([]float32, n)
+		for i := range floats {
+			floats[i] = float32(elem(i).Float())
+		}
+		return protobuf.FloatFeature(floats...), nil
+	default:
+		bytes := make([][]byte, n)
+		for i := range bytes {
+			if e := elem(i); e.Kind() == reflect.String {
+				bytes[i] = []byte(e.String())
+			} else {
+				bytes[i] = e.Bytes()
+			}
+		}
+		return protobuf.BytesFeature(bytes...), nil
+	}
+}
+
+func intValue(v reflect.Value) (int64, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	}
+	return v.Int(), nil
+}
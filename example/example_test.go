@@ -0,0 +1,205 @@
+package example
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+type image struct {
+	Encoded []byte `tfr:"encoded"`
+	Height  uint16 `tfr:"height"`
+}
+
+type Meta struct {
+	Source string `tfr:"source"`
+}
+
+type rating struct {
+	Meta
+	User     string     `tfr:"user"`
+	Age      int        `tfr:"age,omitempty"`
+	Label    bool       `tfr:"label"`
+	Movies   []string   `tfr:"movie"`
+	Scores   []float64  `tfr:"movie_ratings"`
+	Box      [2]float32 `tfr:"box"`
+	Weight   *float32   `tfr:"weight"`
+	Image    image      `tfr:"image"`
+	Internal string     `tfr:"-"`
+	Year     int32
+	private  int
+}
+
+func TestMarshal(t *testing.T) {
+	r := rating{
+		Meta:   Meta{Source: "web"},
+		User:   "u1",
+		Label:  true,
+		Movies: []string{"Heat", "Up"},
+		Scores: []float64{9.5, 7},
+		Box:    [2]float32{0.25, 0.75},
+		Image:  image{Encoded: []byte{0xff, 0xd8}, Height: 480},
+		Year:   1995,
+	}
+	want := protobuf.NewExample().
+		Strings("source", "web").
+		Strings("user", "u1").
+		Int64("label", 1).
+		Strings("movie", "Heat", "Up").
+		Float("movie_ratings", 9.5, 7).
+		Float("box", 0.25, 0.75).
+		Bytes("image/encoded", []byte{0xff, 0xd8}).
+		Int64("image/height", 480).
+		Int64("Year", 1995).
+		Build()
+
+	got, err := Marshal(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var decoded rating
+	if err := Unmarshal(got, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, r) {
+		t.Errorf("got %+v after a round trip, want %+v", decoded, r)
+	}
+
+	weight := float32(0.5)
+	r.Age, r.Weight = 29, &weight
+	got, err = Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ages, err := got.Int64s("age"); err != nil || ages[0] != 29 {
+		t.Errorf("got ages %v, %v", ages, err)
+	}
+	decoded = rating{}
+	if err := Unmarshal(got, &decoded); err != nil || decoded.Weight == nil || *decoded.Weight != weight {
+		t.Errorf("got weight %v, %v", decoded.Weight, err)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	var tests = []struct {
+		desc string
+		v    interface{}
+		err  string
+	}{
+		{"not a struct", 42, "cannot marshal int, want a struct"},
+		{"unsupported type", struct{ M map[string]int }{}, "field M: unsupported type map[string]int"},
+		{"overflow", struct{ N uint64 }{math.MaxUint64}, "feature N: value 18446744073709551615 overflows int64"},
+		{"duplicate", struct {
+			A int `tfr:"x"`
+			B int `tfr:"x"`
+		}{}, `struct { A int "tfr:\"x\""; B int "tfr:\"x\"" }: several fields map to feature "x"`},
+		{"steps", session{}, "field events holds steps, use MarshalSequence"},
+	}
+	for _, tt := range tests {
+		if _, err := Marshal(tt.v); err == nil || err.Error() != tt.err {
+			t.Errorf("%s: got %v, want %s", tt.desc, err, tt.err)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	example := protobuf.NewExample().Int64("age", 300, 2).Float("score", 0.5).Build()
+
+	var kindErr *protobuf.KindError
+	if err := Unmarshal(example, &struct {
+		Score string `tfr:"score"`
+	}{}); !errors.As(err, &kindErr) || kindErr.Kind != "float" || kindErr.Want != "bytes" {
+		t.Errorf("got %v, want a KindError", err)
+	}
+
+	var tests = []struct {
+		desc string
+		v    interface{}
+		err  string
+	}{
+		{"not a pointer", struct{}{}, "cannot unmarshal into struct {}, want a non-nil pointer to a struct"},
+		{"several values", &struct {
+			Age int `tfr:"age"`
+		}{}, "feature age: cannot store 2 values in int"},
+		{"overflow", &struct {
+			Age []int8 `tfr:"age"`
+		}{}, "feature age: value 300 overflows int8"},
+		{"array length", &struct {
+			Age [3]int `tfr:"age"`
+		}{}, "feature age: cannot store 2 values in [3]int"},
+	}
+	for _, tt := range tests {
+		if err := Unmarshal(example, tt.v); err == nil || err.Error() != tt.err {
+			t.Errorf("%s: got %v, want %s", tt.desc, err, tt.err)
+		}
+	}
+}
+
+type event struct {
+	Movie  string  `tfr:"movie"`
+	Rating float32 `tfr:"rating"`
+	Tags   []string
+}
+
+type session struct {
+	User   string  `tfr:"user"`
+	Events []event `tfr:"events"`
+	Clicks []struct {
+		Position int `tfr:"position"`
+	} `tfr:",inline"`
+}
+
+func TestMarshalSequence(t *testing.T) {
+	s := session{
+		User:   "u1",
+		Events: []event{{Movie: "Heat", Rating: 9, Tags: []string{"crime"}}, {Movie: "Up", Rating: 8}},
+	}
+	s.Clicks = append(s.Clicks, struct {
+		Position int `tfr:"position"`
+	}{3})
+	want := protobuf.NewSequenceExample().
+		Strings("user", "u1").
+		StringSteps("events/movie", []string{"Heat"}, []string{"Up"}).
+		FloatSteps("events/rating", []float32{9}, []float32{8}).
+		StringSteps("events/Tags", []string{"crime"}, []string{}).
+		Int64Steps("position", []int64{3}).
+		Build()
+
+	got, err := MarshalSequence(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var decoded session
+	if err := UnmarshalSequence(got, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	s.Events[1].Tags = []string{}
+	if !reflect.DeepEqual(decoded, s) {
+		t.Errorf("got %+v after a round trip, want %+v", decoded, s)
+	}
+
+	// Feature lists of different lengths fill the steps they have.
+	ragged := protobuf.NewSequenceExample().
+		StringSteps("events/movie", []string{"Heat"}, []string{"Up"}).
+		FloatSteps("events/rating", []float32{9}).
+		Build()
+	decoded = session{}
+	if err := UnmarshalSequence(ragged, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Events) != 2 || decoded.Events[1].Movie != "Up" || decoded.Events[1].Rating != 0 {
+		t.Errorf("got events %+v", decoded.Events)
+	}
+}
@@ -0,0 +1,222 @@
+// Package example converts between Go structs and tf.train.Example or
+// tf.train.SequenceExample records, the way encoding/json converts between
+// structs and JSON objects.
+//
+// Each exported field becomes a feature named after its tfr struct tag, or
+// the field name when there is none:
+//
+//	type Rating struct {
+//		User   string    `tfr:"user"`
+//		Age    int       `tfr:"age,omitempty"`
+//		Movies []string  `tfr:"movie"`
+//		Scores []float32 `tfr:"movie_ratings"`
+//		Image  struct {
+//			Encoded []byte `tfr:"encoded"`
+//			Height  int    `tfr:"height"`
+//		} `tfr:"image"`
+//		Internal string `tfr:"-"`
+//	}
+//
+// Integers and bools are stored as int64 features, floats as float features
+// and strings and []byte as bytes features. Slices and arrays of them hold
+// several values, nil pointers to them are left out. The fields of nested
+// structs are prefixed with the name of the struct field and a slash, as
+// image/encoded above, unless the struct is embedded without a tag or
+// tagged inline. Fields tagged omitempty are left out when empty.
+//
+// MarshalSequence and UnmarshalSequence also map slices of structs to
+// feature lists, one step per element, with the fields of the struct
+// named like those of nested structs.
+package example
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Feature kinds, named after the protobuf.Feature lists.
+const (
+	kindBytes = "bytes"
+	kindInt64 = "int64"
+	kindFloat = "float"
+)
+
+// field describes the feature a struct field maps to.
+type field struct {
+	name string
+	// index is the path of the field from the struct, or the element of
+	// the slice for steps, as for reflect.Value.FieldByIndex.
+	index     []int
+	kind      string
+	list      bool
+	omitEmpty bool
+	// steps is set for slices of structs and holds the fields of their
+	// elements, each mapping to a feature list.
+	steps []*field
+}
+
+type cachedFields struct {
+	fields []*field
+	err    error
+}
+
+var fieldCache sync.Map // map[reflect.Type]cachedFields
+
+// typeFields returns the fields of the struct type t.
+func typeFields(t reflect.Type) ([]*field, error) {
+	if c, ok := fieldCache.Load(t); ok {
+		return c.(cachedFields).fields, c.(cachedFields).err
+	}
+	fields, err := appendFields(nil, t, "", nil, false)
+	if err == nil {
+		err = checkDuplicates(fields, t)
+	}
+	fieldCache.Store(t, cachedFields{fields, err})
+	return fields, err
+}
+
+func appendFields(fields []*field, t reflect.Type, prefix string, index []int, inSteps bool) ([]*field, error) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		embeddedStruct := sf.Anonymous && sf.Type.Kind() == reflect.Struct
+		if sf.PkgPath != "" && !embeddedStruct {
+			continue
+		}
+		tag := sf.Tag.Get("tfr")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		if name == "" {
+			name = sf.Name
+		}
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		ft := sf.Type
+		switch {
+		case ft.Kind() == reflect.Struct:
+			nested := prefix + name + "/"
+			if (embeddedStruct && tag == "") || opts.contains("inline") {
+				nested = prefix
+			}
+			var err error
+			if fields, err = appendFields(fields, ft, nested, fieldIndex, inSteps); err != nil {
+				return nil, err
+			}
+			continue
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct:
+			if inSteps {
+				return nil, fmt.Errorf("field %s: steps cannot hold steps", sf.Name)
+			}
+			stepPrefix := prefix + name + "/"
+			if opts.contains("inline") {
+				stepPrefix = prefix
+			}
+			steps, err := appendFields(nil, ft.Elem(), stepPrefix, nil, true)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, &field{name: prefix + name, index: fieldIndex, omitEmpty: opts.contains("omitempty"), steps: steps})
+			continue
+		}
+
+		kind, list, ok := typeKind(ft)
+		if !ok {
+			return nil, fmt.Errorf("field %s: unsupported type %s", sf.Name, ft)
+		}
+		fields = append(fields, &field{
+			name:      prefix + name,
+			index:     fieldIndex,
+			kind:      kind,
+			list:      list,
+			omitEmpty: opts.contains("omitempty"),
+		})
+	}
+	return fields, nil
+}
+
+// typeKind returns the kind of feature values of type t are stored as, and
+// whether t holds a list of them.
+func typeKind(t reflect.Type) (kind string, list bool, ok bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if kind, ok := scalarKind(t); ok {
+		return kind, false, true
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		kind, ok := scalarKind(t.Elem())
+		return kind, true, ok
+	}
+	return "", false, false
+}
+
+func scalarKind(t reflect.Type) (string, bool) {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kindInt64, true
+	case reflect.Float32, reflect.Float64:
+		return kindFloat, true
+	case reflect.String:
+		return kindBytes, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return kindBytes, true
+		}
+	}
+	return "", false
+}
+
+// checkDuplicates reports features, or feature lists, that several fields
+// of t map to.
+func checkDuplicates(fields []*field, t reflect.Type) error {
+	features, lists := map[string]bool{}, map[string]bool{}
+	for _, f := range fields {
+		if f.steps == nil {
+			if features[f.name] {
+				return fmt.Errorf("%s: several fields map to feature %q", t, f.name)
+			}
+			features[f.name] = true
+			continue
+		}
+		for _, step := range f.steps {
+			if lists[step.name] {
+				return fmt.Errorf("%s: several fields map to feature list %q", t, step.name)
+			}
+			lists[step.name] = true
+		}
+	}
+	return nil
+}
+
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, tagOptions(opts)
+}
+
+func (o tagOptions) contains(option string) bool {
+	for _, opt := range strings.Split(string(o), ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// structValue returns the struct v holds or points to.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("cannot marshal %T, want a struct", v)
+	}
+	return rv, nil
+}
@@ -0,0 +1,413 @@
+// Package filter implements the small expression language used to select
+// records, e.g.
+//
+//	label == 1 && len(movie) > 2
+//	any(movie_ratings, _ >= 9.5) || movie[0] =~ "^The "
+//	has(user) && !all(movie, _ == "Fight Club")
+//
+// Bare names refer to features; names with other characters than letters,
+// digits, "_", "/" and "." are quoted with backticks. A feature evaluates to
+// the list of its values. Comparisons unwrap single-valued lists and are
+// false when either side is a missing feature or out of range index.
+// Multi-valued lists must be indexed or compared through any and all, whose
+// second argument is evaluated for each value bound to "_".
+package filter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Expr is a compiled filter expression.
+type Expr struct {
+	root     node
+	features []string
+}
+
+// Compile parses src into an expression.
+func Compile(src string) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, tokens: tokens, refs: map[string]bool{}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected token")
+	}
+
+	e := &Expr{root: root}
+	for name := range p.refs {
+		e.features = append(e.features, name)
+	}
+	sort.Strings(e.features)
+	return e, nil
+}
+
+// Features returns the names of the features referenced by e.
+func (e *Expr) Features() []string {
+	return e.features
+}
+
+// Match evaluates e against the flattened features of a record, as returned
+// by utils.FlattenRecord. Feature values are []int64, []float32, []string or,
+// for feature lists, []interface{} of those.
+func (e *Expr) Match(features map[string]interface{}) (bool, error) {
+	s := &scope{features: features}
+	v, err := s.eval(e.root)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s: expected a boolean, got %s", e.root.source(), kindOf(v))
+	}
+	return b, nil
+}
+
+// missing is the value of an absent feature or an out of range index.
+type missing struct{}
+
+type scope struct {
+	features map[string]interface{}
+	elem     interface{}
+	inLoop   bool
+}
+
+func (s *scope) eval(n node) (interface{}, error) {
+	switch n := n.(type) {
+	case *literal:
+		return n.value, nil
+
+	case *ref:
+		if n.name == "_" && s.inLoop {
+			return s.elem, nil
+		}
+		if v, ok := s.features[n.name]; ok && v != nil {
+			return v, nil
+		}
+		return missing{}, nil
+
+	case *indexExpr:
+		return s.evalIndex(n)
+
+	case *call:
+		return s.evalCall(n)
+
+	case *unary:
+		b, err := s.evalBool(n.x)
+		if err != nil {
+			return nil, err
+		}
+		return !b, nil
+
+	case *binary:
+		switch n.op {
+		case "&&", "||":
+			x, err := s.evalBool(n.x)
+			if err != nil {
+				return nil, err
+			}
+			if x == (n.op == "||") {
+				return x, nil
+			}
+			return s.evalBool(n.y)
+		case "=~", "!~":
+			return s.evalMatch(n)
+		}
+		return s.evalComparison(n)
+	}
+	return nil, fmt.Errorf("%s: unknown expression", n.source())
+}
+
+func (s *scope) evalBool(n node) (bool, error) {
+	v, err := s.eval(n)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s: expected a boolean, got %s", n.source(), kindOf(v))
+	}
+	return b, nil
+}
+
+func (s *scope) evalIndex(n *indexExpr) (interface{}, error) {
+	x, err := s.eval(n.x)
+	if err != nil {
+		return nil, err
+	}
+	index, err := s.eval(n.index)
+	if err != nil {
+		return nil, err
+	}
+	i, ok := index.(int64)
+	if !ok {
+		return nil, fmt.Errorf("%s: index must be an integer, got %s", n.index.source(), kindOf(index))
+	}
+	if _, ok := x.(missing); ok {
+		return missing{}, nil
+	}
+	length, ok := listLen(x)
+	if !ok {
+		return nil, fmt.Errorf("%s: cannot index %s", n.source(), kindOf(x))
+	}
+	if i < 0 {
+		i += int64(length)
+	}
+	if i < 0 || i >= int64(length) {
+		return missing{}, nil
+	}
+	return listElem(x, int(i)), nil
+}
+
+func (s *scope) evalCall(n *call) (interface{}, error) {
+	switch n.fn {
+	case "has":
+		_, ok := s.features[n.args[0].(*ref).name]
+		return ok, nil
+
+	case "len":
+		x, err := s.eval(n.args[0])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := x.(missing); ok {
+			return int64(0), nil
+		}
+		if str, ok := x.(string); ok {
+			return int64(len(str)), nil
+		}
+		length, ok := listLen(x)
+		if !ok {
+			return nil, fmt.Errorf("%s: len of %s", n.source(), kindOf(x))
+		}
+		return int64(length), nil
+
+	case "any", "all":
+		x, err := s.eval(n.args[0])
+		if err != nil {
+			return nil, err
+		}
+		all := n.fn == "all"
+		if _, ok := x.(missing); ok {
+			return all, nil
+		}
+		length, ok := listLen(x)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s over %s", n.source(), n.fn, kindOf(x))
+		}
+		inner := &scope{features: s.features, inLoop: true}
+		for i := 0; i < length; i++ {
+			inner.elem = listElem(x, i)
+			b, err := inner.evalBool(n.args[1])
+			if err != nil {
+				return nil, err
+			}
+			if b != all {
+				return b, nil
+			}
+		}
+		return all, nil
+	}
+	return nil, fmt.Errorf("%s: unknown function %s", n.source(), n.fn)
+}
+
+func (s *scope) evalMatch(n *binary) (interface{}, error) {
+	x, err := s.evalScalar(n.x)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := x.(missing); ok {
+		return false, nil
+	}
+	str, ok := x.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s: cannot match %s against a pattern", n.source(), kindOf(x))
+	}
+	return n.re.MatchString(str) == (n.op == "=~"), nil
+}
+
+func (s *scope) evalComparison(n *binary) (interface{}, error) {
+	x, err := s.evalScalar(n.x)
+	if err != nil {
+		return nil, err
+	}
+	y, err := s.evalScalar(n.y)
+	if err != nil {
+		return nil, err
+	}
+	_, xMissing := x.(missing)
+	_, yMissing := y.(missing)
+	if xMissing || yMissing {
+		return false, nil
+	}
+
+	var cmp int
+	switch x := x.(type) {
+	case int64, float64:
+		yf, ok := toFloat(y)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot compare %s with %s", n.source(), kindOf(x), kindOf(y))
+		}
+		if xi, ok := x.(int64); ok {
+			if yi, ok := y.(int64); ok {
+				cmp = compareInt(xi, yi)
+				break
+			}
+		}
+		xf, _ := toFloat(x)
+		if math.IsNaN(xf) || math.IsNaN(yf) {
+			// NaN is unordered, so only != holds.
+			return n.op == "!=", nil
+		}
+		cmp = compareFloat(xf, yf)
+	case string:
+		ys, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot compare %s with %s", n.source(), kindOf(x), kindOf(y))
+		}
+		cmp = compareString(x, ys)
+	case bool:
+		yb, ok := y.(bool)
+		if !ok || n.op != "==" && n.op != "!=" {
+			return nil, fmt.Errorf("%s: cannot compare %s with %s using %s", n.source(), kindOf(x), kindOf(y), n.op)
+		}
+		if x != yb {
+			cmp = 1
+		}
+	default:
+		return nil, fmt.Errorf("%s: cannot compare %s", n.source(), kindOf(x))
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+// evalScalar evaluates n and unwraps single-valued lists. Empty lists are
+// treated as missing.
+func (s *scope) evalScalar(n node) (interface{}, error) {
+	v, err := s.eval(n)
+	if err != nil {
+		return nil, err
+	}
+	length, ok := listLen(v)
+	if !ok {
+		return v, nil
+	}
+	switch length {
+	case 0:
+		return missing{}, nil
+	case 1:
+		return listElem(v, 0), nil
+	}
+	return nil, fmt.Errorf("%s: %s has %d values, use an index, any or all", n.source(), kindOf(v), length)
+}
+
+func listLen(v interface{}) (int, bool) {
+	switch v := v.(type) {
+	case []int64:
+		return len(v), true
+	case []float32:
+		return len(v), true
+	case []string:
+		return len(v), true
+	case []interface{}:
+		return len(v), true
+	}
+	return 0, false
+}
+
+func listElem(v interface{}, i int) interface{} {
+	switch v := v.(type) {
+	case []int64:
+		return v[i]
+	case []float32:
+		return float64(v[i])
+	case []string:
+		return v[i]
+	case []interface{}:
+		return v[i]
+	}
+	return missing{}
+}
+
+// kindOf names the type of v after the feature kinds.
+func kindOf(v interface{}) string {
+	switch v.(type) {
+	case int64:
+		return "int64"
+	case float64:
+		return "float"
+	case string:
+		return "bytes"
+	case bool:
+		return "bool"
+	case []int64:
+		return "int64 list"
+	case []float32:
+		return "float list"
+	case []string:
+		return "bytes list"
+	case []interface{}:
+		return "feature list"
+	case missing:
+		return "missing value"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func compareInt(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareString(x, y string) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
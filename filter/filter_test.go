@@ -0,0 +1,137 @@
+package filter
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var record = map[string]interface{}{
+	"age":           []int64{29},
+	"label":         []int64{1},
+	"movie":         []string{"The Shawshank Redemption", "Fight Club"},
+	"movie_ratings": []float32{9, 9.7},
+	"score":         []float32{float32(math.NaN())},
+	"empty":         []int64{},
+	"image/encoded": []string{"\x89PNG"},
+	"actors": []interface{}{
+		[]string{"Tim Robbins", "Morgan Freeman"},
+		[]string{"Brad Pitt", "Edward Norton", "Helena Bonham Carter"},
+	},
+}
+
+var matchTests = []struct {
+	expr string
+	want bool
+}{
+	{`label == 1`, true},
+	{`label == 1 && len(movie) > 2`, false},
+	{`label == 1 && len(movie) >= 2`, true},
+	{`age > 28.5`, true},
+	{`age != 29`, false},
+	{`-1 < age`, true},
+	{`movie[0] == "The Shawshank Redemption"`, true},
+	{`movie[-1] == 'Fight Club'`, true},
+	{`movie[2] == "Fight Club"`, false},
+	{`movie[1] < "G"`, true},
+	{`movie[0] =~ "^The "`, true},
+	{`movie[1] !~ "^The "`, true},
+	{`any(movie, _ =~ "Club")`, true},
+	{`all(movie_ratings, _ > 9)`, false},
+	{`all(movie_ratings, _ >= 9)`, true},
+	{`any(missing, _ > 0)`, false},
+	{`all(missing, _ > 0)`, true},
+	{`has(age) && !has(missing)`, true},
+	{`missing == 1 || missing != 1`, false},
+	{`empty == 1`, false},
+	{`len(missing) == 0`, true},
+	{"len(`image/encoded`[0]) == 4", true},
+	{`has(image/encoded)`, true},
+	{`score == score || score < 1`, false},
+	{`score != 1`, true},
+	{`len(actors) == 2 && any(actors, len(_) == 3)`, true},
+	{`any(actors, any(_, _ == "Brad Pitt"))`, true},
+	{`(label == 0 || age == 29) && true`, true},
+	{`1e1 == 10`, true},
+}
+
+func TestMatch(t *testing.T) {
+	for _, tt := range matchTests {
+		e, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("%s: compile error: %v", tt.expr, err)
+			continue
+		}
+		got, err := e.Match(record)
+		if err != nil {
+			t.Errorf("%s: match error: %v", tt.expr, err)
+		} else if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+var errorTests = []struct {
+	expr string
+	err  string
+}{
+	{`label == "1"`, `label == "1": cannot compare int64 with bytes`},
+	{`movie == "Fight Club"`, `movie: bytes list has 2 values, use an index, any or all`},
+	{`movie[0] > 1`, `movie[0] > 1: cannot compare bytes with int64`},
+	{`age =~ "2"`, `age =~ "2": cannot match int64 against a pattern`},
+	{`age`, `age: expected a boolean, got int64 list`},
+	{`!age`, `age: expected a boolean, got int64 list`},
+	{`movie["a"] == "b"`, `"a": index must be an integer, got bytes`},
+	{`len(age[0]) == 1`, `len(age[0]): len of int64`},
+	{`label == 1 &&`, `expected an expression at 13, found end of expression`},
+	{`has("age")`, `has("age"): has takes a feature name`},
+	{`len(a, b)`, `len(a, b): len takes 1 argument(s), got 2`},
+	{`movie =~ label`, `movie =~ label: =~ needs a string pattern`},
+	{`age == 1 1`, `unexpected token at 9, found 1`},
+	{`age == "1`, `unterminated string at 7`},
+}
+
+func TestErrors(t *testing.T) {
+	for _, tt := range errorTests {
+		e, err := Compile(tt.expr)
+		if err == nil {
+			_, err = e.Match(record)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got error %v, want %q", tt.expr, err, tt.err)
+		}
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	e, err := Compile(`label == 1 && any(movie, _ =~ "Club") && has(age) && len(movie) > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(e.Features(), ","); got != "age,label,movie" {
+		t.Errorf("got features %s, want age,label,movie", got)
+	}
+}
+
+var stringTests = []struct {
+	src  string
+	want string
+}{
+	{`"Fight Club"`, "Fight Club"},
+	{`'Fight Club'`, "Fight Club"},
+	{`'it\'s'`, "it's"},
+	{`"it\'s"`, "it's"},
+	{`'a"b'`, `a"b`},
+	{`'a\"b'`, `a"b`},
+	{`"a\"b"`, `a"b`},
+	{`'tab\tnew\\line'`, "tab\tnew\\line"},
+}
+
+func TestLexStrings(t *testing.T) {
+	for _, tt := range stringTests {
+		tokens, err := lex(tt.src)
+		if err != nil || len(tokens) != 2 || tokens[0].kind != tokString || tokens[0].text != tt.want {
+			t.Errorf("%s: got %+v, %v, want %q", tt.src, tokens, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string // operator or identifier, unquoted string or number text
+	pos  int    // byte offsets of the token in the source
+	end  int
+}
+
+// operators are ordered so that longer operators are matched first.
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=", "=~", "!~",
+	"<", ">", "!", "(", ")", "[", "]", ",", "-",
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isIdentPart allows "/" and "." so that feature names such as
+// image/encoded can be written without quoting.
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '/' || r == '.'
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+
+		case isIdentStart(r):
+			start := i
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if !isIdentPart(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{tokIdent, src[start:i], start, i})
+
+		case r == '`':
+			end := strings.IndexByte(src[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated feature name at %d", i)
+			}
+			tokens = append(tokens, token{tokIdent, src[i+1 : i+1+end], i, i + end + 2})
+			i += end + 2
+
+		case r >= '0' && r <= '9':
+			start := i
+			for i < len(src) {
+				c := src[i]
+				exponentSign := (c == '+' || c == '-') && (src[i-1] == 'e' || src[i-1] == 'E')
+				if !(c >= '0' && c <= '9' || c == '.' || c == 'e' || c == 'E' || exponentSign) {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, src[start:i], start, i})
+
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != byte(r) {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			s, err := unquote(src[i+1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %v", i, err)
+			}
+			tokens = append(tokens, token{tokString, s, i, end + 1})
+			i = end + 1
+
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", src[i:i+size], i)
+			}
+			tokens = append(tokens, token{tokOp, op, i, i + len(op)})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src), len(src)}), nil
+}
+
+// unquote returns the string literal body, between single or double quotes,
+// with its Go escapes interpreted. Both quotes may be escaped in either kind
+// of literal, and double quotes need not be in single quoted ones.
+func unquote(body string) (string, error) {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '\\' && i+1 < len(body) && body[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == '\\' && i+1 < len(body):
+			b.WriteString(body[i : i+2])
+			i++
+		case c == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return strconv.Unquote(b.String())
+}
@@ -0,0 +1,300 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// node is an expression in the syntax tree. src is the source text of the
+// expression, used in error messages.
+type node interface {
+	source() string
+}
+
+type literal struct {
+	src   string
+	value interface{} // bool, int64, float64 or string
+}
+
+// ref refers to a feature by name, or to the current element inside any
+// and all when name is "_".
+type ref struct {
+	src  string
+	name string
+}
+
+type indexExpr struct {
+	src   string
+	x     node
+	index node
+}
+
+type call struct {
+	src  string
+	fn   string
+	args []node
+}
+
+type unary struct {
+	src string
+	op  string
+	x   node
+}
+
+type binary struct {
+	src  string
+	op   string
+	x, y node
+	re   *regexp.Regexp // compiled pattern for =~ and !~
+}
+
+func (n *literal) source() string   { return n.src }
+func (n *ref) source() string       { return n.src }
+func (n *indexExpr) source() string { return n.src }
+func (n *call) source() string      { return n.src }
+func (n *unary) source() string     { return n.src }
+func (n *binary) source() string    { return n.src }
+
+// functions maps the builtin functions to their number of arguments.
+var functions = map[string]int{
+	"len": 1,
+	"has": 1,
+	"any": 2,
+	"all": 2,
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+	refs   map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// text returns the source between the start of token start and the end of
+// the last consumed token.
+func (p *parser) text(start int) string {
+	return p.src[p.tokens[start].pos:p.tokens[p.pos-1].end]
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("expected %q", op)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	found := t.text
+	if t.kind == tokEOF {
+		found = "end of expression"
+	}
+	return fmt.Errorf("%s at %d, found %s", fmt.Sprintf(format, args...), t.pos, found)
+}
+
+func (p *parser) parseOr() (node, error) {
+	start := p.pos
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &binary{src: p.text(start), op: "||", x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	start := p.pos
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = &binary{src: p.text(start), op: "&&", x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	start := p.pos
+	if p.isOp("!") {
+		p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{src: p.text(start), op: "!", x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	start := p.pos
+	x, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp("==", "!=", "<", "<=", ">", ">=", "=~", "!~") {
+		return x, nil
+	}
+	op := p.next().text
+	y, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	b := &binary{src: p.text(start), op: op, x: x, y: y}
+	if op == "=~" || op == "!~" {
+		pattern, ok := y.(*literal)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s needs a string pattern", b.src, op)
+		}
+		s, ok := pattern.value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s needs a string pattern", b.src, op)
+		}
+		if b.re, err = regexp.Compile(s); err != nil {
+			return nil, fmt.Errorf("%s: %v", b.src, err)
+		}
+	}
+	return b, nil
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	start := p.pos
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("[") {
+		p.next()
+		index, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		x = &indexExpr{src: p.text(start), x: x, index: index}
+	}
+	return x, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	start := p.pos
+	switch t := p.peek(); {
+	case t.kind == tokNumber:
+		p.next()
+		return parseNumber(t.text, t.text)
+
+	case t.kind == tokOp && t.text == "-":
+		p.next()
+		n := p.next()
+		if n.kind != tokNumber {
+			p.pos--
+			return nil, p.errorf("expected a number after \"-\"")
+		}
+		return parseNumber("-"+n.text, p.text(start))
+
+	case t.kind == tokString:
+		p.next()
+		return &literal{src: p.text(start), value: t.text}, nil
+
+	case t.kind == tokOp && t.text == "(":
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+
+	case t.kind == tokIdent:
+		p.next()
+		if t.text == "true" || t.text == "false" {
+			return &literal{src: t.text, value: t.text == "true"}, nil
+		}
+		if arity, ok := functions[t.text]; ok && p.isOp("(") {
+			return p.parseCall(start, t.text, arity)
+		}
+		if t.text != "_" {
+			p.refs[t.text] = true
+		}
+		return &ref{src: p.text(start), name: t.text}, nil
+	}
+	return nil, p.errorf("expected an expression")
+}
+
+func (p *parser) parseCall(start int, fn string, arity int) (node, error) {
+	p.next() // (
+	var args []node
+	for !p.isOp(")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next() // )
+
+	c := &call{src: p.text(start), fn: fn, args: args}
+	if len(args) != arity {
+		return nil, fmt.Errorf("%s: %s takes %d argument(s), got %d", c.src, fn, arity, len(args))
+	}
+	if _, ok := args[0].(*ref); fn == "has" && !ok {
+		return nil, fmt.Errorf("%s: has takes a feature name", c.src)
+	}
+	return c, nil
+}
+
+func parseNumber(text, src string) (node, error) {
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return &literal{src: src, value: i}, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", text)
+	}
+	return &literal{src: src, value: f}, nil
+}
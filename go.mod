@@ -1,10 +1,17 @@
 module github.com/emla2805/tfr
 
-go 1.15
+go 1.23
 
 require (
 	github.com/spf13/cobra v1.1.1
+	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68
+	golang.org/x/term v0.0.0-20201126162022-7de9c90e9dd1
 	google.golang.org/protobuf v1.25.0
 )
 
+require (
+	github.com/inconshreveable/mousetrap v1.0.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+)
+
 replace github.com/tensorflow/tensorflow/tensorflow/go/core => ./proto/tensorflow/core
@@ -0,0 +1,162 @@
+package tfr
+
+// ExampleBuilder builds an Example one feature at a time:
+//
+//	example := NewExample().Int64("age", 29).Strings("movie", "The Shining").Build()
+//
+// Setting a feature twice replaces it.
+type ExampleBuilder struct {
+	features map[string]*Feature
+}
+
+// NewExample returns an ExampleBuilder for an Example without features.
+func NewExample() *ExampleBuilder {
+	return &ExampleBuilder{features: map[string]*Feature{}}
+}
+
+// Int64 sets the int64 feature name to values.
+func (b *ExampleBuilder) Int64(name string, values ...int64) *ExampleBuilder {
+	b.features[name] = Int64Feature(values...)
+	return b
+}
+
+// Float sets the float feature name to values.
+func (b *ExampleBuilder) Float(name string, values ...float32) *ExampleBuilder {
+	b.features[name] = FloatFeature(values...)
+	return b
+}
+
+// Bytes sets the bytes feature name to values.
+func (b *ExampleBuilder) Bytes(name string, values ...[]byte) *ExampleBuilder {
+	b.features[name] = BytesFeature(values...)
+	return b
+}
+
+// Strings sets the bytes feature name to values.
+func (b *ExampleBuilder) Strings(name string, values ...string) *ExampleBuilder {
+	b.features[name] = StringFeature(values...)
+	return b
+}
+
+// Build returns the Example. The builder must not be used afterwards.
+func (b *ExampleBuilder) Build() *Example {
+	return &Example{Features: &Features{Feature: b.features}}
+}
+
+// SequenceExampleBuilder builds a SequenceExample: context features are set
+// like those of an ExampleBuilder and feature lists from their steps.
+//
+//	example := NewSequenceExample().
+//		Int64("user", 7).
+//		Int64Steps("ratings", []int64{5}, []int64{3, 4}).
+//		Build()
+type SequenceExampleBuilder struct {
+	context ExampleBuilder
+	lists   map[string]*FeatureList
+}
+
+// NewSequenceExample returns a SequenceExampleBuilder for a
+// SequenceExample without features.
+func NewSequenceExample() *SequenceExampleBuilder {
+	return &SequenceExampleBuilder{
+		context: ExampleBuilder{features: map[string]*Feature{}},
+		lists:   map[string]*FeatureList{},
+	}
+}
+
+// Int64 sets the int64 context feature name to values.
+func (b *SequenceExampleBuilder) Int64(name string, values ...int64) *SequenceExampleBuilder {
+	b.context.Int64(name, values...)
+	return b
+}
+
+// Float sets the float context feature name to values.
+func (b *SequenceExampleBuilder) Float(name string, values ...float32) *SequenceExampleBuilder {
+	b.context.Float(name, values...)
+	return b
+}
+
+// Bytes sets the bytes context feature name to values.
+func (b *SequenceExampleBuilder) Bytes(name string, values ...[]byte) *SequenceExampleBuilder {
+	b.context.Bytes(name, values...)
+	return b
+}
+
+// Strings sets the bytes context feature name to values.
+func (b *SequenceExampleBuilder) Strings(name string, values ...string) *SequenceExampleBuilder {
+	b.context.Strings(name, values...)
+	return b
+}
+
+// Int64Steps sets the int64 feature list name, with one step per slice.
+func (b *SequenceExampleBuilder) Int64Steps(name string, steps ...[]int64) *SequenceExampleBuilder {
+	list := &FeatureList{Feature: make([]*Feature, len(steps))}
+	for i, step := range steps {
+		list.Feature[i] = Int64Feature(step...)
+	}
+	b.lists[name] = list
+	return b
+}
+
+// FloatSteps sets the float feature list name, with one step per slice.
+func (b *SequenceExampleBuilder) FloatSteps(name string, steps ...[]float32) *SequenceExampleBuilder {
+	list := &FeatureList{Feature: make([]*Feature, len(steps))}
+	for i, step := range steps {
+		list.Feature[i] = FloatFeature(step...)
+	}
+	b.lists[name] = list
+	return b
+}
+
+// BytesSteps sets the bytes feature list name, with one step per slice.
+func (b *SequenceExampleBuilder) BytesSteps(name string, steps ...[][]byte) *SequenceExampleBuilder {
+	list := &FeatureList{Feature: make([]*Feature, len(steps))}
+	for i, step := range steps {
+		list.Feature[i] = BytesFeature(step...)
+	}
+	b.lists[name] = list
+	return b
+}
+
+// StringSteps sets the bytes feature list name, with one step per slice.
+func (b *SequenceExampleBuilder) StringSteps(name string, steps ...[]string) *SequenceExampleBuilder {
+	list := &FeatureList{Feature: make([]*Feature, len(steps))}
+	for i, step := range steps {
+		list.Feature[i] = StringFeature(step...)
+	}
+	b.lists[name] = list
+	return b
+}
+
+// Build returns the SequenceExample. The builder must not be used
+// afterwards.
+func (b *SequenceExampleBuilder) Build() *SequenceExample {
+	return &SequenceExample{
+		Context:      &Features{Feature: b.context.features},
+		FeatureLists: &FeatureLists{FeatureList: b.lists},
+	}
+}
+
+// Int64Feature returns an int64 feature holding values.
+func Int64Feature(values ...int64) *Feature {
+	return &Feature{Kind: &Feature_Int64List{Int64List: &Int64List{Value: values}}}
+}
+
+// FloatFeature returns a float feature holding values.
+func FloatFeature(values ...float32) *Feature {
+	return &Feature{Kind: &Feature_FloatList{FloatList: &FloatList{Value: values}}}
+}
+
+// BytesFeature returns a bytes feature holding values.
+func BytesFeature(values ...[]byte) *Feature {
+	return &Feature{Kind: &Feature_BytesList{BytesList: &BytesList{Value: values}}}
+}
+
+// StringFeature returns a bytes feature holding values.
+func StringFeature(values ...string) *Feature {
+	bytes := make([][]byte, len(values))
+	for i, v := range values {
+		bytes[i] = []byte(v)
+	}
+	return &Feature{Kind: &Feature_BytesList{BytesList: &BytesList{Value: bytes}}}
+}
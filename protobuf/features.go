@@ -0,0 +1,181 @@
+package tfr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned, wrapped, by the accessors of features that are
+// not present.
+var ErrNotFound = errors.New("not found")
+
+// KindError is returned by the accessors of features of another kind than
+// the one asked for.
+type KindError struct {
+	Name string
+	// Kind is the kind of the feature, one of bytes, float, int64 or none
+	// when no list is set, and Want the one asked for.
+	Kind, Want string
+}
+
+func (e *KindError) Error() string {
+	return fmt.Sprintf("feature %q is %s, not %s", e.Name, e.Kind, e.Want)
+}
+
+// KindName returns the kind of the feature: bytes, float, int64, or none
+// when no list is set.
+func (x *Feature) KindName() string {
+	switch x.GetKind().(type) {
+	case *Feature_BytesList:
+		return "bytes"
+	case *Feature_FloatList:
+		return "float"
+	case *Feature_Int64List:
+		return "int64"
+	}
+	return "none"
+}
+
+func (x *Features) lookup(name, want string) (*Feature, error) {
+	f, ok := x.GetFeature()[name]
+	if !ok {
+		return nil, fmt.Errorf("feature %q %w", name, ErrNotFound)
+	}
+	if kind := f.KindName(); kind != want {
+		return nil, &KindError{Name: name, Kind: kind, Want: want}
+	}
+	return f, nil
+}
+
+// Int64s returns the values of the int64 feature name.
+func (x *Features) Int64s(name string) ([]int64, error) {
+	f, err := x.lookup(name, "int64")
+	if err != nil {
+		return nil, err
+	}
+	return f.GetInt64List().GetValue(), nil
+}
+
+// Floats returns the values of the float feature name.
+func (x *Features) Floats(name string) ([]float32, error) {
+	f, err := x.lookup(name, "float")
+	if err != nil {
+		return nil, err
+	}
+	return f.GetFloatList().GetValue(), nil
+}
+
+// Bytes returns the values of the bytes feature name.
+func (x *Features) Bytes(name string) ([][]byte, error) {
+	f, err := x.lookup(name, "bytes")
+	if err != nil {
+		return nil, err
+	}
+	return f.GetBytesList().GetValue(), nil
+}
+
+// Strings returns the values of the bytes feature name as strings.
+func (x *Features) Strings(name string) ([]string, error) {
+	values, err := x.Bytes(name)
+	if err != nil {
+		return nil, err
+	}
+	return toStrings(values), nil
+}
+
+// Int64s returns the values of the int64 feature name of the Example.
+func (x *Example) Int64s(name string) ([]int64, error) {
+	return x.GetFeatures().Int64s(name)
+}
+
+// Floats returns the values of the float feature name of the Example.
+func (x *Example) Floats(name string) ([]float32, error) {
+	return x.GetFeatures().Floats(name)
+}
+
+// Bytes returns the values of the bytes feature name of the Example.
+func (x *Example) Bytes(name string) ([][]byte, error) {
+	return x.GetFeatures().Bytes(name)
+}
+
+// Strings returns the values of the bytes feature name of the Example as
+// strings.
+func (x *Example) Strings(name string) ([]string, error) {
+	return x.GetFeatures().Strings(name)
+}
+
+// lookup returns the steps of the feature list name, which must all be of
+// kind want.
+func (x *FeatureLists) lookup(name, want string) ([]*Feature, error) {
+	list, ok := x.GetFeatureList()[name]
+	if !ok {
+		return nil, fmt.Errorf("feature list %q %w", name, ErrNotFound)
+	}
+	for _, f := range list.GetFeature() {
+		if kind := f.KindName(); kind != want {
+			return nil, &KindError{Name: name, Kind: kind, Want: want}
+		}
+	}
+	return list.GetFeature(), nil
+}
+
+// Int64s returns the values of each step of the int64 feature list name.
+func (x *FeatureLists) Int64s(name string) ([][]int64, error) {
+	steps, err := x.lookup(name, "int64")
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]int64, len(steps))
+	for i, f := range steps {
+		values[i] = f.GetInt64List().GetValue()
+	}
+	return values, nil
+}
+
+// Floats returns the values of each step of the float feature list name.
+func (x *FeatureLists) Floats(name string) ([][]float32, error) {
+	steps, err := x.lookup(name, "float")
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]float32, len(steps))
+	for i, f := range steps {
+		values[i] = f.GetFloatList().GetValue()
+	}
+	return values, nil
+}
+
+// Bytes returns the values of each step of the bytes feature list name.
+func (x *FeatureLists) Bytes(name string) ([][][]byte, error) {
+	steps, err := x.lookup(name, "bytes")
+	if err != nil {
+		return nil, err
+	}
+	values := make([][][]byte, len(steps))
+	for i, f := range steps {
+		values[i] = f.GetBytesList().GetValue()
+	}
+	return values, nil
+}
+
+// Strings returns the values of each step of the bytes feature list name as
+// strings.
+func (x *FeatureLists) Strings(name string) ([][]string, error) {
+	steps, err := x.Bytes(name)
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]string, len(steps))
+	for i, step := range steps {
+		values[i] = toStrings(step)
+	}
+	return values, nil
+}
+
+func toStrings(values [][]byte) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return s
+}
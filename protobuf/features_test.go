@@ -0,0 +1,72 @@
+package tfr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExampleAccessors(t *testing.T) {
+	example := NewExample().
+		Int64("age", 29).
+		Float("rating", 9.5, 7).
+		Bytes("image", []byte{0xff, 0xd8}).
+		Strings("movie", "Heat", "Up").
+		Build()
+
+	if ages, err := example.Int64s("age"); err != nil || !reflect.DeepEqual(ages, []int64{29}) {
+		t.Errorf("got ages %v, %v", ages, err)
+	}
+	if ratings, err := example.Floats("rating"); err != nil || !reflect.DeepEqual(ratings, []float32{9.5, 7}) {
+		t.Errorf("got ratings %v, %v", ratings, err)
+	}
+	if images, err := example.Bytes("image"); err != nil || !reflect.DeepEqual(images, [][]byte{{0xff, 0xd8}}) {
+		t.Errorf("got images %v, %v", images, err)
+	}
+	if movies, err := example.Strings("movie"); err != nil || !reflect.DeepEqual(movies, []string{"Heat", "Up"}) {
+		t.Errorf("got movies %v, %v", movies, err)
+	}
+
+	var kindErr *KindError
+	if _, err := example.Floats("age"); !errors.As(err, &kindErr) || *kindErr != (KindError{Name: "age", Kind: "int64", Want: "float"}) {
+		t.Errorf("got %v for the wrong kind, want a KindError", err)
+	}
+	if _, err := example.Int64s("year"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for a missing feature, want ErrNotFound", err)
+	}
+	if _, err := (*Example)(nil).Strings("movie"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for a nil Example, want ErrNotFound", err)
+	}
+	if err := (&KindError{Name: "age", Kind: "int64", Want: "float"}).Error(); err != `feature "age" is int64, not float` {
+		t.Errorf("got error %q", err)
+	}
+}
+
+func TestSequenceExampleAccessors(t *testing.T) {
+	example := NewSequenceExample().
+		Int64("user", 7).
+		Int64Steps("ratings", []int64{5}, []int64{3, 4}).
+		FloatSteps("scores", []float32{0.5}).
+		StringSteps("movies", []string{"Heat"}, nil).
+		Build()
+
+	if users, err := example.GetContext().Int64s("user"); err != nil || !reflect.DeepEqual(users, []int64{7}) {
+		t.Errorf("got users %v, %v", users, err)
+	}
+	if ratings, err := example.GetFeatureLists().Int64s("ratings"); err != nil || !reflect.DeepEqual(ratings, [][]int64{{5}, {3, 4}}) {
+		t.Errorf("got ratings %v, %v", ratings, err)
+	}
+	if scores, err := example.GetFeatureLists().Floats("scores"); err != nil || !reflect.DeepEqual(scores, [][]float32{{0.5}}) {
+		t.Errorf("got scores %v, %v", scores, err)
+	}
+	if movies, err := example.GetFeatureLists().Strings("movies"); err != nil || !reflect.DeepEqual(movies, [][]string{{"Heat"}, {}}) {
+		t.Errorf("got movies %v, %v", movies, err)
+	}
+	var kindErr *KindError
+	if _, err := example.GetFeatureLists().Bytes("ratings"); !errors.As(err, &kindErr) {
+		t.Errorf("got %v for the wrong kind, want a KindError", err)
+	}
+	if _, err := example.GetFeatureLists().Int64s("user"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for a context feature, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,395 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// IntDomain bounds the values of an int64 feature.
+type IntDomain struct {
+	Min *int64 `json:"min,omitempty"`
+	Max *int64 `json:"max,omitempty"`
+}
+
+// FloatDomain bounds the values of a float feature.
+type FloatDomain struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// Read reads a schema written by WritePbtxt or as JSON, or any TensorFlow
+// Metadata schema.pbtxt. Features of a schema.pbtxt without a value count
+// accept any number of values.
+func Read(r io.Reader) (*Schema, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		s := &Schema{}
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	root, err := parseText(string(data))
+	if err != nil {
+		return nil, err
+	}
+	vocabularies := map[string][]string{}
+	for _, d := range root.all("string_domain") {
+		vocabularies[d.value("name")] = d.values("value")
+	}
+
+	s := &Schema{}
+	for _, f := range root.all("feature") {
+		if f.value("name") == SequenceFeatureName {
+			s.Sequence = true
+			for _, sf := range f.get("struct_domain").all("feature") {
+				feature, err := readFeature(sf, vocabularies, true)
+				if err != nil {
+					return nil, err
+				}
+				s.FeatureLists = append(s.FeatureLists, feature)
+			}
+			continue
+		}
+		feature, err := readFeature(f, vocabularies, false)
+		if err != nil {
+			return nil, err
+		}
+		s.Features = append(s.Features, feature)
+	}
+	return s, nil
+}
+
+// kinds maps TFMD feature types to kinds.
+var kinds = map[string]string{
+	"BYTES": KindBytes,
+	"INT":   KindInt64,
+	"FLOAT": KindFloat,
+}
+
+func readFeature(n *textNode, vocabularies map[string][]string, sequence bool) (*Feature, error) {
+	f := &Feature{Name: n.value("name"), MaxCount: math.MaxInt32, MaxSteps: math.MaxInt32}
+	if t := n.value("type"); t != "" {
+		kind, ok := kinds[t]
+		if !ok {
+			return nil, fmt.Errorf("feature %q: unsupported type %s", f.Name, t)
+		}
+		f.Kind = kind
+	}
+
+	var err error
+	if presence := n.get("presence"); presence != nil {
+		if v := presence.value("min_fraction"); v != "" {
+			if f.Presence, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("feature %q: %v", f.Name, err)
+			}
+		}
+	}
+
+	counts := []*textNode{n.get("value_count")}
+	if vc := n.get("value_counts"); vc != nil {
+		counts = vc.all("value_count")
+	}
+	if sequence && len(counts) == 2 {
+		if f.MinSteps, f.MaxSteps, err = readRange(counts[0]); err != nil {
+			return nil, fmt.Errorf("feature %q: %v", f.Name, err)
+		}
+		counts = counts[1:]
+	}
+	if len(counts) > 0 && counts[0] != nil {
+		if f.MinCount, f.MaxCount, err = readRange(counts[0]); err != nil {
+			return nil, fmt.Errorf("feature %q: %v", f.Name, err)
+		}
+	}
+	if shape := n.get("shape"); shape != nil {
+		size := 1
+		for _, dim := range shape.all("dim") {
+			d, err := strconv.Atoi(dim.value("size"))
+			if err != nil {
+				return nil, fmt.Errorf("feature %q: invalid dim size: %v", f.Name, err)
+			}
+			size *= d
+		}
+		f.MinCount, f.MaxCount = size, size
+	}
+
+	if d := n.get("int_domain"); d != nil {
+		f.IntDomain = &IntDomain{}
+		for _, bound := range []struct {
+			name string
+			dst  **int64
+		}{{"min", &f.IntDomain.Min}, {"max", &f.IntDomain.Max}} {
+			if v := d.value(bound.name); v != "" {
+				i, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("feature %q: invalid int_domain: %v", f.Name, err)
+				}
+				*bound.dst = &i
+			}
+		}
+	}
+	if d := n.get("float_domain"); d != nil {
+		f.FloatDomain = &FloatDomain{}
+		for _, bound := range []struct {
+			name string
+			dst  **float64
+		}{{"min", &f.FloatDomain.Min}, {"max", &f.FloatDomain.Max}} {
+			if v := d.value(bound.name); v != "" {
+				x, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					return nil, fmt.Errorf("feature %q: invalid float_domain: %v", f.Name, err)
+				}
+				*bound.dst = &x
+			}
+		}
+	}
+	if d := n.get("string_domain"); d != nil {
+		f.Vocabulary = d.values("value")
+	}
+	if name := n.value("domain"); name != "" {
+		vocabulary, ok := vocabularies[name]
+		if !ok {
+			return nil, fmt.Errorf("feature %q: unknown domain %q", f.Name, name)
+		}
+		f.Vocabulary = vocabulary
+	}
+	return f, nil
+}
+
+func readRange(n *textNode) (min, max int, err error) {
+	max = math.MaxInt32
+	if v := n.value("min"); v != "" {
+		if min, err = strconv.Atoi(v); err != nil {
+			return 0, 0, err
+		}
+	}
+	if v := n.value("max"); v != "" {
+		if max, err = strconv.Atoi(v); err != nil {
+			return 0, 0, err
+		}
+	}
+	return min, max, nil
+}
+
+// textNode is a field of a message in protobuf text format. Scalar fields
+// have a value, message fields have fields.
+type textNode struct {
+	name   string
+	scalar string
+	fields []*textNode
+}
+
+func (n *textNode) all(name string) []*textNode {
+	if n == nil {
+		return nil
+	}
+	var found []*textNode
+	for _, f := range n.fields {
+		if f.name == name {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+func (n *textNode) get(name string) *textNode {
+	if found := n.all(name); len(found) > 0 {
+		return found[len(found)-1]
+	}
+	return nil
+}
+
+func (n *textNode) value(name string) string {
+	if f := n.get(name); f != nil {
+		return f.scalar
+	}
+	return ""
+}
+
+func (n *textNode) values(name string) []string {
+	var values []string
+	for _, f := range n.all(name) {
+		values = append(values, f.scalar)
+	}
+	return values
+}
+
+// parseText parses protobuf text format into a tree of fields, without a
+// message descriptor. Unknown fields are therefore kept, not rejected.
+func parseText(src string) (*textNode, error) {
+	p := &textParser{src: src}
+	root := &textNode{}
+	if err := p.parseFields(root, ""); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+type textParser struct {
+	src string
+	pos int
+}
+
+func (p *textParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' || c == ';':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *textParser) errorf(format string, args ...interface{}) error {
+	line := 1 + strings.Count(p.src[:p.pos], "\n")
+	return fmt.Errorf("schema line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// parseFields parses fields into n until the closing delimiter end, or the
+// end of input when end is empty.
+func (p *textParser) parseFields(n *textNode, end string) error {
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			if end != "" {
+				return p.errorf("expected %q", end)
+			}
+			return nil
+		}
+		if end != "" && strings.HasPrefix(p.src[p.pos:], end) {
+			p.pos++
+			return nil
+		}
+
+		name := p.word()
+		if name == "" {
+			return p.errorf("expected a field name, found %q", p.src[p.pos:p.pos+1])
+		}
+		p.skipSpace()
+		colon := p.pos < len(p.src) && p.src[p.pos] == ':'
+		if colon {
+			p.pos++
+			p.skipSpace()
+		}
+		if p.pos >= len(p.src) {
+			return p.errorf("expected a value for %s", name)
+		}
+
+		switch c := p.src[p.pos]; c {
+		case '{', '<':
+			p.pos++
+			field := &textNode{name: name}
+			closing := "}"
+			if c == '<' {
+				closing = ">"
+			}
+			if err := p.parseFields(field, closing); err != nil {
+				return err
+			}
+			n.fields = append(n.fields, field)
+		case '[':
+			p.pos++
+			for {
+				p.skipSpace()
+				if p.pos < len(p.src) && p.src[p.pos] == ']' {
+					p.pos++
+					break
+				}
+				value, err := p.scalar()
+				if err != nil {
+					return err
+				}
+				n.fields = append(n.fields, &textNode{name: name, scalar: value})
+			}
+		default:
+			if !colon {
+				return p.errorf("expected \":\" after %s", name)
+			}
+			value, err := p.scalar()
+			if err != nil {
+				return err
+			}
+			n.fields = append(n.fields, &textNode{name: name, scalar: value})
+		}
+	}
+}
+
+// word consumes an identifier or number.
+func (p *textParser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !(c == '_' || c == '.' || c == '-' || c == '+' ||
+			c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// scalar consumes a value, concatenating adjacent strings.
+func (p *textParser) scalar() (string, error) {
+	if p.pos >= len(p.src) || p.src[p.pos] != '"' && p.src[p.pos] != '\'' {
+		if w := p.word(); w != "" {
+			return w, nil
+		}
+		return "", p.errorf("expected a value")
+	}
+	var b strings.Builder
+	for p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		quote := p.src[p.pos]
+		end := p.pos + 1
+		for end < len(p.src) && p.src[end] != quote {
+			if p.src[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(p.src) {
+			return "", p.errorf("unterminated string")
+		}
+		s, err := unquote(p.src[p.pos+1:end], quote)
+		if err != nil {
+			return "", p.errorf("invalid string: %v", err)
+		}
+		b.WriteString(s)
+		p.pos = end + 1
+		for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+	}
+	return b.String(), nil
+}
+
+func unquote(s string, quote byte) (string, error) {
+	var b strings.Builder
+	for len(s) > 0 {
+		r, multibyte, tail, err := strconv.UnquoteChar(s, quote)
+		if err != nil {
+			return "", err
+		}
+		if multibyte {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte(byte(r))
+		}
+		s = tail
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,209 @@
+// Package schema infers a feature schema from Examples and SequenceExamples.
+package schema
+
+import (
+	"sort"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/utils"
+	"google.golang.org/protobuf/proto"
+)
+
+// Feature kinds, named after the protobuf.Feature lists.
+const (
+	KindBytes = "bytes"
+	KindInt64 = "int64"
+	KindFloat = "float"
+)
+
+// Feature summarises a feature across the records it was inferred from. For
+// feature lists of SequenceExamples the value counts are per step.
+type Feature struct {
+	Name string `json:"name"`
+	// Kind is the kind seen most often.
+	Kind string `json:"kind"`
+	// Kinds counts the records per kind. More than one entry is a conflict.
+	Kinds map[string]int `json:"kinds"`
+	// Present is the number of records holding the feature.
+	Present  int     `json:"present"`
+	Presence float64 `json:"presence"`
+	MinCount int     `json:"min_count"`
+	MaxCount int     `json:"max_count"`
+	// MinSteps and MaxSteps are the number of steps of a feature list.
+	MinSteps int `json:"min_steps,omitempty"`
+	MaxSteps int `json:"max_steps,omitempty"`
+	// IntDomain, FloatDomain and Vocabulary constrain the values of a
+	// feature. They are only set on schemas read from a file.
+	IntDomain   *IntDomain   `json:"int_domain,omitempty"`
+	FloatDomain *FloatDomain `json:"float_domain,omitempty"`
+	Vocabulary  []string     `json:"vocabulary,omitempty"`
+
+	counted bool
+}
+
+// Conflict reports whether the feature was seen with more than one kind.
+func (f *Feature) Conflict() bool {
+	return len(f.Kinds) > 1
+}
+
+// FixedCount reports whether every value of the feature has the same number
+// of values.
+func (f *Feature) FixedCount() bool {
+	return f.MinCount == f.MaxCount
+}
+
+// Schema is the inferred schema of a dataset.
+type Schema struct {
+	Records int `json:"records"`
+	// Sequence is set when the records are SequenceExamples, in which case
+	// Features holds the context features.
+	Sequence     bool       `json:"sequence"`
+	Features     []*Feature `json:"features"`
+	FeatureLists []*Feature `json:"feature_lists,omitempty"`
+}
+
+// Builder infers a Schema from records added one at a time.
+type Builder struct {
+	records      int
+	sequence     bool
+	features     map[string]*Feature
+	featureLists map[string]*Feature
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		features:     map[string]*Feature{},
+		featureLists: map[string]*Feature{},
+	}
+}
+
+// Add adds a protobuf.Example or protobuf.SequenceExample to the schema.
+func (b *Builder) Add(m proto.Message) {
+	b.records++
+	switch m := m.(type) {
+	case *protobuf.Example:
+		b.addFeatures(b.features, m.GetFeatures())
+	case *protobuf.SequenceExample:
+		b.sequence = true
+		b.addFeatures(b.features, m.GetContext())
+		for name, list := range m.GetFeatureLists().GetFeatureList() {
+			f := feature(b.featureLists, name)
+			steps := len(list.GetFeature())
+			if f.Present == 0 || steps < f.MinSteps {
+				f.MinSteps = steps
+			}
+			if steps > f.MaxSteps {
+				f.MaxSteps = steps
+			}
+			kinds := map[string]bool{}
+			for _, step := range list.GetFeature() {
+				kind, count := Kind(step)
+				kinds[kind] = true
+				f.addCount(count)
+			}
+			for kind := range kinds {
+				f.Kinds[kind]++
+			}
+			f.Present++
+		}
+	}
+}
+
+// AddView adds the Example viewed by v to the schema, without decoding the
+// values of its features.
+func (b *Builder) AddView(v *utils.ExampleView) error {
+	b.records++
+	for name, value := range v.All() {
+		count, err := value.Count()
+		if err != nil {
+			return err
+		}
+		kind := value.Kind()
+		if kind == "none" {
+			kind = KindBytes
+		}
+		f := feature(b.features, name)
+		f.Kinds[kind]++
+		f.addCount(count)
+		f.Present++
+	}
+	return nil
+}
+
+func (b *Builder) addFeatures(features map[string]*Feature, fs *protobuf.Features) {
+	for name, value := range fs.GetFeature() {
+		f := feature(features, name)
+		kind, count := Kind(value)
+		f.Kinds[kind]++
+		f.addCount(count)
+		f.Present++
+	}
+}
+
+func (f *Feature) addCount(count int) {
+	if !f.counted || count < f.MinCount {
+		f.MinCount = count
+	}
+	f.counted = true
+	if count > f.MaxCount {
+		f.MaxCount = count
+	}
+}
+
+func feature(features map[string]*Feature, name string) *Feature {
+	f, ok := features[name]
+	if !ok {
+		f = &Feature{Name: name, Kinds: map[string]int{}}
+		features[name] = f
+	}
+	return f
+}
+
+// Schema returns the schema inferred from the records added so far, with
+// features sorted by name.
+func (b *Builder) Schema() *Schema {
+	return &Schema{
+		Records:      b.records,
+		Sequence:     b.sequence,
+		Features:     finish(b.features, b.records),
+		FeatureLists: finish(b.featureLists, b.records),
+	}
+}
+
+func finish(features map[string]*Feature, records int) []*Feature {
+	list := make([]*Feature, 0, len(features))
+	for _, f := range features {
+		f.Kind = mostCommon(f.Kinds)
+		if records > 0 {
+			f.Presence = float64(f.Present) / float64(records)
+		}
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func mostCommon(kinds map[string]int) string {
+	best := ""
+	for kind, n := range kinds {
+		if best == "" || n > kinds[best] || n == kinds[best] && kind < best {
+			best = kind
+		}
+	}
+	return best
+}
+
+// Kind returns the kind of f and its number of values. A feature without a
+// kind set is reported as an empty bytes list, as TensorFlow parses it.
+func Kind(f *protobuf.Feature) (kind string, count int) {
+	switch k := f.GetKind().(type) {
+	case *protobuf.Feature_Int64List:
+		return KindInt64, len(k.Int64List.GetValue())
+	case *protobuf.Feature_FloatList:
+		return KindFloat, len(k.FloatList.GetValue())
+	case *protobuf.Feature_BytesList:
+		return KindBytes, len(k.BytesList.GetValue())
+	}
+	return KindBytes, 0
+}
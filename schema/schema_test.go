@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+)
+
+func TestInferExamples(t *testing.T) {
+	b := NewBuilder()
+	b.Add(protobuf.NewExample().
+		Int64("age", 29).
+		Strings("movie", "The Shawshank Redemption", "Fight Club").
+		Int64("score", 1).
+		Build())
+	b.Add(protobuf.NewExample().
+		Int64("age", 31).
+		Strings("movie", "The Godfather").
+		Float("score", 0.5).
+		Build())
+	b.Add(protobuf.NewExample().
+		Int64("age", 24).
+		Strings("movie", "Inception", "Pulp Fiction", "Heat").
+		Build())
+
+	s := b.Schema()
+	if s.Records != 3 || s.Sequence || len(s.Features) != 3 {
+		t.Fatalf("got %d records, sequence %v, %d features", s.Records, s.Sequence, len(s.Features))
+	}
+	age, movie, score := s.Features[0], s.Features[1], s.Features[2]
+	if age.Kind != KindInt64 || !age.FixedCount() || age.MinCount != 1 || age.Presence != 1 {
+		t.Errorf("age: %+v", age)
+	}
+	if movie.Kind != KindBytes || movie.MinCount != 1 || movie.MaxCount != 3 {
+		t.Errorf("movie: %+v", movie)
+	}
+	if !score.Conflict() || score.Present != 2 || score.Presence != 2.0/3 {
+		t.Errorf("score: %+v", score)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePbtxt(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	want := `feature {
+  name: "age"
+  type: INT
+  presence {
+    min_fraction: 1.0
+    min_count: 1
+  }
+  shape {
+    dim {
+      size: 1
+    }
+  }
+}
+feature {
+  name: "movie"
+  type: BYTES
+  presence {
+    min_fraction: 1.0
+    min_count: 1
+  }
+  value_count {
+    min: 1
+    max: 3
+  }
+}
+`
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("pbtxt:\ngot:\n%s\nwant prefix:\n%s", buf.String(), want)
+	}
+}
+
+func TestInferSequenceExamples(t *testing.T) {
+	b := NewBuilder()
+	b.Add(protobuf.NewSequenceExample().
+		Int64("age", 29).
+		FloatSteps("ratings", []float32{9}, []float32{9.7, 8}).
+		Build())
+	b.Add(protobuf.NewSequenceExample().
+		FloatSteps("ratings", []float32{7}, []float32{1}, []float32{2}).
+		Build())
+
+	s := b.Schema()
+	if !s.Sequence || len(s.Features) != 1 || len(s.FeatureLists) != 1 {
+		t.Fatalf("got sequence %v, %d features, %d feature lists", s.Sequence, len(s.Features), len(s.FeatureLists))
+	}
+	if age := s.Features[0]; age.Presence != 0.5 {
+		t.Errorf("age: %+v", age)
+	}
+	ratings := s.FeatureLists[0]
+	if ratings.Kind != KindFloat || ratings.MinCount != 1 || ratings.MaxCount != 2 ||
+		ratings.MinSteps != 2 || ratings.MaxSteps != 3 || ratings.Presence != 1 {
+		t.Errorf("ratings: %+v", ratings)
+	}
+}
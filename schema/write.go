@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// SequenceFeatureName is the name of the STRUCT feature TensorFlow Data
+// Validation nests the feature lists of SequenceExamples under.
+const SequenceFeatureName = "##SEQUENCE##"
+
+// WriteText writes a human-readable summary of s.
+func WriteText(w io.Writer, s *Schema) error {
+	if !s.Sequence {
+		fmt.Fprintf(w, "%d examples\n\n", s.Records)
+		return writeFeatureTable(w, s.Features, false)
+	}
+	fmt.Fprintf(w, "%d sequence examples\n\ncontext\n", s.Records)
+	if err := writeFeatureTable(w, s.Features, false); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nfeature lists")
+	return writeFeatureTable(w, s.FeatureLists, true)
+}
+
+func writeFeatureTable(w io.Writer, features []*Feature, sequence bool) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := "feature\tkind\tpresence\tvalues"
+	if sequence {
+		header += "\tsteps"
+	}
+	fmt.Fprintln(tw, header)
+	for _, f := range features {
+		line := fmt.Sprintf("%s\t%s\t%.1f%%\t%s", f.Name, kindSummary(f), 100*f.Presence, countRange(f.MinCount, f.MaxCount))
+		if sequence {
+			line += "\t" + countRange(f.MinSteps, f.MaxSteps)
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
+
+// kindSummary names the kind of f, listing every kind with its record count
+// when there is a conflict.
+func kindSummary(f *Feature) string {
+	if !f.Conflict() {
+		return f.Kind
+	}
+	kinds := make([]string, 0, len(f.Kinds))
+	for kind, n := range f.Kinds {
+		kinds = append(kinds, fmt.Sprintf("%s:%d", kind, n))
+	}
+	sort.Strings(kinds)
+	return "CONFLICT(" + strings.Join(kinds, ",") + ")"
+}
+
+func countRange(min, max int) string {
+	if min == max {
+		return strconv.Itoa(min)
+	}
+	return fmt.Sprintf("%d-%d", min, max)
+}
+
+// WritePbtxt writes s as a TensorFlow Metadata Schema in protobuf text
+// format, the schema.pbtxt understood by TensorFlow Data Validation and
+// TensorFlow Transform.
+func WritePbtxt(w io.Writer, s *Schema) error {
+	p := &pbtxtWriter{w: w}
+	for _, f := range s.Features {
+		p.feature(f, false)
+	}
+	if s.Sequence && len(s.FeatureLists) > 0 {
+		p.open("feature")
+		p.field("name", strconv.Quote(SequenceFeatureName))
+		p.field("type", "STRUCT")
+		p.open("presence")
+		p.field("min_count", "1")
+		p.close()
+		p.open("struct_domain")
+		for _, f := range s.FeatureLists {
+			p.feature(f, true)
+		}
+		p.close()
+		p.close()
+	}
+	return p.err
+}
+
+// pbtxtTypes maps kinds to TFMD FeatureType values.
+var pbtxtTypes = map[string]string{
+	KindBytes: "BYTES",
+	KindInt64: "INT",
+	KindFloat: "FLOAT",
+}
+
+type pbtxtWriter struct {
+	w      io.Writer
+	indent int
+	err    error
+}
+
+func (p *pbtxtWriter) line(s string) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, "%s%s\n", strings.Repeat("  ", p.indent), s)
+	}
+}
+
+func (p *pbtxtWriter) open(name string) {
+	p.line(name + " {")
+	p.indent++
+}
+
+func (p *pbtxtWriter) close() {
+	p.indent--
+	p.line("}")
+}
+
+func (p *pbtxtWriter) field(name, value string) {
+	p.line(name + ": " + value)
+}
+
+func (p *pbtxtWriter) feature(f *Feature, sequence bool) {
+	p.open("feature")
+	p.field("name", strconv.Quote(f.Name))
+	if t, ok := pbtxtTypes[f.Kind]; ok {
+		p.field("type", t)
+	}
+	p.open("presence")
+	if f.Presence == 1 {
+		p.field("min_fraction", "1.0")
+	} else {
+		p.field("min_fraction", strconv.FormatFloat(f.Presence, 'g', -1, 64))
+	}
+	p.field("min_count", "1")
+	p.close()
+	switch {
+	case sequence:
+		p.open("value_counts")
+		p.valueCount(f.MinSteps, f.MaxSteps)
+		p.valueCount(f.MinCount, f.MaxCount)
+		p.close()
+	case f.FixedCount() && f.Presence == 1:
+		p.open("shape")
+		p.open("dim")
+		p.field("size", strconv.Itoa(f.MinCount))
+		p.close()
+		p.close()
+	default:
+		p.valueCount(f.MinCount, f.MaxCount)
+	}
+	p.close()
+}
+
+func (p *pbtxtWriter) valueCount(min, max int) {
+	p.open("value_count")
+	p.field("min", strconv.Itoa(min))
+	p.field("max", strconv.Itoa(max))
+	p.close()
+}
@@ -0,0 +1,134 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// featureTypes lists the feature types each feature dict accepts.
+var featureTypes = map[string][]string{
+	"features":          {FixedLen, VarLen, Ragged, Sparse, FixedLenSequence},
+	"context_features":  {FixedLen, VarLen, Ragged},
+	"sequence_features": {FixedLenSequence, VarLen, Ragged},
+}
+
+// Read reads a spec in the JSON format written by "tfr spec --lang json",
+// rejecting specs tf.io.parse_example or tf.io.parse_sequence_example would
+// not accept.
+func Read(r io.Reader) (*Spec, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	s := &Spec{}
+	if err := dec.Decode(s); err != nil {
+		return nil, err
+	}
+	if s.Features != nil && (s.ContextFeatures != nil || s.SequenceFeatures != nil) {
+		return nil, fmt.Errorf("features cannot be combined with context_features or sequence_features")
+	}
+	for dict, features := range map[string]map[string]*Feature{
+		"features":          s.Features,
+		"context_features":  s.ContextFeatures,
+		"sequence_features": s.SequenceFeatures,
+	} {
+		for name, f := range features {
+			err := f.check(featureTypes[dict])
+			if err == nil && dict == "features" && f.Type == FixedLenSequence && !f.AllowMissing {
+				err = fmt.Errorf("%s requires allow_missing in features", FixedLenSequence)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s[%q]: %v", dict, name, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+func (f *Feature) check(types []string) error {
+	if f == nil {
+		return fmt.Errorf("missing feature spec")
+	}
+	if !containsString(types, f.Type) {
+		return fmt.Errorf("unsupported type %q", f.Type)
+	}
+	if _, ok := pythonDtypes[f.Dtype]; !ok {
+		return fmt.Errorf("unsupported dtype %q", f.Dtype)
+	}
+	for _, d := range f.Shape {
+		if d < 0 {
+			return fmt.Errorf("invalid shape %v", f.Shape)
+		}
+	}
+
+	switch f.Type {
+	case FixedLen:
+		if f.Default == nil {
+			break
+		}
+		values, err := defaultValues(f.Default, f.Dtype)
+		if err != nil {
+			return err
+		}
+		if n := len(values); n != f.Elements() && !(n == 1 && len(f.Shape) == 0) {
+			return fmt.Errorf("default_value has %d values, shape %v needs %d", n, f.Shape, f.Elements())
+		}
+	case FixedLenSequence:
+		if f.Default != nil {
+			return fmt.Errorf("default_value is not supported")
+		}
+	case Sparse:
+		if len(f.IndexKey) == 0 || f.ValueKey == "" {
+			return fmt.Errorf("index_key and value_key are required")
+		}
+		if len(f.Size) != len(f.IndexKey) {
+			return fmt.Errorf("size has %d dimensions, index_key %d", len(f.Size), len(f.IndexKey))
+		}
+	}
+	if f.AllowMissing && f.Type != FixedLenSequence {
+		return fmt.Errorf("allow_missing is only supported by %s", FixedLenSequence)
+	}
+	return nil
+}
+
+// defaultValues flattens a JSON default value, checking its values match
+// dtype.
+func defaultValues(v interface{}, dtype string) ([]interface{}, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		list = []interface{}{v}
+	}
+	var values []interface{}
+	for _, item := range list {
+		if nested, ok := item.([]interface{}); ok {
+			flat, err := defaultValues(nested, dtype)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, flat...)
+			continue
+		}
+		switch x := item.(type) {
+		case float64:
+			if dtype == DtypeString || dtype == DtypeInt64 && x != float64(int64(x)) {
+				return nil, fmt.Errorf("default_value %v is not %s", x, dtype)
+			}
+		case string:
+			if dtype != DtypeString {
+				return nil, fmt.Errorf("default_value %q is not %s", x, dtype)
+			}
+		default:
+			return nil, fmt.Errorf("invalid default_value %v", item)
+		}
+		values = append(values, item)
+	}
+	return values, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
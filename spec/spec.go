@@ -0,0 +1,220 @@
+// Package spec describes TensorFlow feature specs, the feature dicts passed
+// to tf.io.parse_example and tf.io.parse_sequence_example, and derives them
+// from an inferred schema.
+package spec
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/emla2805/tfr/schema"
+)
+
+// Feature spec types, named after their tf.io classes.
+const (
+	FixedLen         = "FixedLenFeature"
+	VarLen           = "VarLenFeature"
+	Ragged           = "RaggedFeature"
+	FixedLenSequence = "FixedLenSequenceFeature"
+	Sparse           = "SparseFeature"
+)
+
+// Dtypes of parsed features.
+const (
+	DtypeInt64   = "int64"
+	DtypeFloat32 = "float32"
+	DtypeString  = "string"
+)
+
+// Feature is the spec of a single feature. Shape is only used by fixed
+// length features, where an absent shape means a scalar.
+type Feature struct {
+	Type    string      `json:"type"`
+	Dtype   string      `json:"dtype"`
+	Shape   []int       `json:"shape,omitempty"`
+	Default interface{} `json:"default_value,omitempty"`
+	// IndexKey, ValueKey and Size describe a SparseFeature.
+	IndexKey []string `json:"index_key,omitempty"`
+	ValueKey string   `json:"value_key,omitempty"`
+	Size     []int    `json:"size,omitempty"`
+	// AllowMissing lets a FixedLenSequenceFeature be absent.
+	AllowMissing bool `json:"allow_missing,omitempty"`
+}
+
+// Elements returns the number of values of a fixed length feature, or of
+// each step of a FixedLenSequenceFeature.
+func (f *Feature) Elements() int {
+	n := 1
+	for _, d := range f.Shape {
+		n *= d
+	}
+	return n
+}
+
+// String returns the feature spec as Python.
+func (f *Feature) String() string {
+	return pythonFeature(f)
+}
+
+// Spec holds the feature spec of Examples, or the context and sequence
+// feature specs of SequenceExamples.
+type Spec struct {
+	Features         map[string]*Feature `json:"features,omitempty"`
+	ContextFeatures  map[string]*Feature `json:"context_features,omitempty"`
+	SequenceFeatures map[string]*Feature `json:"sequence_features,omitempty"`
+}
+
+// dtypes maps feature kinds to the dtype they parse to.
+var dtypes = map[string]string{
+	schema.KindBytes: DtypeString,
+	schema.KindInt64: DtypeInt64,
+	schema.KindFloat: DtypeFloat32,
+}
+
+// dtype returns the dtype features of kind parse to. Feature lists whose
+// steps are all empty have no kind, and parse as bytes, as schema.Kind
+// reports for empty features.
+func dtype(kind string) string {
+	if d, ok := dtypes[kind]; ok {
+		return d
+	}
+	return DtypeString
+}
+
+// FromSchema derives a spec from an inferred schema. Features with the same
+// number of values in every record become fixed length features, others
+// become VarLenFeatures, or RaggedFeatures when ragged is set. Feature lists
+// missing from some records are allowed to be, and those without any value
+// are variable length bytes.
+func FromSchema(s *schema.Schema, ragged bool) *Spec {
+	varLen := VarLen
+	if ragged {
+		varLen = Ragged
+	}
+
+	features := map[string]*Feature{}
+	for _, f := range s.Features {
+		spec := &Feature{Type: varLen, Dtype: dtype(f.Kind)}
+		if f.FixedCount() && f.Presence == 1 {
+			spec.Type = FixedLen
+			spec.Shape = shape(f.MinCount)
+		}
+		features[f.Name] = spec
+	}
+	if !s.Sequence {
+		return &Spec{Features: features}
+	}
+
+	sequence := map[string]*Feature{}
+	for _, f := range s.FeatureLists {
+		spec := &Feature{Type: varLen, Dtype: dtype(f.Kind)}
+		// Steps without values give no shape to a fixed length.
+		if f.FixedCount() && f.MaxCount > 0 {
+			spec.Type = FixedLenSequence
+			spec.Shape = shape(f.MinCount)
+			spec.AllowMissing = f.Presence < 1
+		}
+		sequence[f.Name] = spec
+	}
+	return &Spec{ContextFeatures: features, SequenceFeatures: sequence}
+}
+
+// shape returns the shape of a feature with count values, a scalar for a
+// single value.
+func shape(count int) []int {
+	if count == 1 {
+		return nil
+	}
+	return []int{count}
+}
+
+// WritePython writes spec as Python feature dicts, ready to be passed to
+// tf.io.parse_example or tf.io.parse_sequence_example.
+func WritePython(w io.Writer, spec *Spec) error {
+	if spec.ContextFeatures == nil && spec.SequenceFeatures == nil {
+		return writePythonDict(w, "feature_description", spec.Features)
+	}
+	if err := writePythonDict(w, "context_features", spec.ContextFeatures); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	return writePythonDict(w, "sequence_features", spec.SequenceFeatures)
+}
+
+var pythonDtypes = map[string]string{
+	DtypeInt64:   "tf.int64",
+	DtypeFloat32: "tf.float32",
+	DtypeString:  "tf.string",
+}
+
+func writePythonDict(w io.Writer, name string, features map[string]*Feature) error {
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(name + " = {\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "    %s: %s,\n", strconv.Quote(name), pythonFeature(features[name]))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func pythonFeature(f *Feature) string {
+	dtype := pythonDtypes[f.Dtype]
+	switch f.Type {
+	case FixedLen, FixedLenSequence:
+		args := []string{pythonInts(f.Shape), dtype}
+		if f.Default != nil {
+			args = append(args, "default_value="+pythonValue(f.Default))
+		}
+		if f.AllowMissing {
+			args = append(args, "allow_missing=True")
+		}
+		return fmt.Sprintf("tf.io.%s(%s)", f.Type, strings.Join(args, ", "))
+	case Sparse:
+		keys := make([]string, len(f.IndexKey))
+		for i, key := range f.IndexKey {
+			keys[i] = strconv.Quote(key)
+		}
+		return fmt.Sprintf("tf.io.%s(index_key=[%s], value_key=%s, dtype=%s, size=%s)",
+			f.Type, strings.Join(keys, ", "), strconv.Quote(f.ValueKey), dtype, pythonInts(f.Size))
+	}
+	return fmt.Sprintf("tf.io.%s(%s)", f.Type, dtype)
+}
+
+func pythonInts(list []int) string {
+	items := make([]string, len(list))
+	for i, d := range list {
+		items[i] = strconv.Itoa(d)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// pythonValue formats a default value decoded from JSON.
+func pythonValue(v interface{}) string {
+	switch v := v.(type) {
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = pythonValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return fmt.Sprint(v)
+}
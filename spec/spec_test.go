@@ -0,0 +1,114 @@
+package spec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emla2805/tfr/schema"
+)
+
+func TestFromSchema(t *testing.T) {
+	s := &schema.Schema{
+		Records: 2,
+		Features: []*schema.Feature{
+			{Name: "age", Kind: schema.KindInt64, Presence: 1, MinCount: 1, MaxCount: 1},
+			{Name: "embedding", Kind: schema.KindFloat, Presence: 1, MinCount: 3, MaxCount: 3},
+			{Name: "movie", Kind: schema.KindBytes, Presence: 1, MinCount: 1, MaxCount: 5},
+			{Name: "user", Kind: schema.KindBytes, Presence: 0.5, MinCount: 1, MaxCount: 1},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WritePython(&buf, FromSchema(s, false)); err != nil {
+		t.Fatal(err)
+	}
+	want := `feature_description = {
+    "age": tf.io.FixedLenFeature([], tf.int64),
+    "embedding": tf.io.FixedLenFeature([3], tf.float32),
+    "movie": tf.io.VarLenFeature(tf.string),
+    "user": tf.io.VarLenFeature(tf.string),
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFromSequenceSchema(t *testing.T) {
+	s := &schema.Schema{
+		Records:  2,
+		Sequence: true,
+		Features: []*schema.Feature{
+			{Name: "age", Kind: schema.KindInt64, Presence: 1, MinCount: 1, MaxCount: 1},
+		},
+		FeatureLists: []*schema.Feature{
+			{Name: "movies", Kind: schema.KindBytes, Presence: 1, MinCount: 1, MaxCount: 3},
+			{Name: "ratings", Kind: schema.KindFloat, Presence: 1, MinCount: 2, MaxCount: 2},
+			{Name: "scores", Kind: schema.KindInt64, Presence: 0.5, MinCount: 1, MaxCount: 1},
+			// Feature lists with only empty steps have no kind.
+			{Name: "tags", Presence: 1},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WritePython(&buf, FromSchema(s, true)); err != nil {
+		t.Fatal(err)
+	}
+	want := `context_features = {
+    "age": tf.io.FixedLenFeature([], tf.int64),
+}
+
+sequence_features = {
+    "movies": tf.io.RaggedFeature(tf.string),
+    "ratings": tf.io.FixedLenSequenceFeature([2], tf.float32),
+    "scores": tf.io.FixedLenSequenceFeature([], tf.int64, allow_missing=True),
+    "tags": tf.io.RaggedFeature(tf.string),
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	var tests = []struct {
+		desc string
+		json string
+		err  string
+	}{
+		{
+			"sparse",
+			`{"features": {"rating": {"type": "SparseFeature", "dtype": "float32", "index_key": ["id"], "value_key": "value", "size": [10]}}}`,
+			"",
+		},
+		{
+			"unknown dtype",
+			`{"features": {"age": {"type": "FixedLenFeature", "dtype": "int32"}}}`,
+			`features["age"]: unsupported dtype "int32"`,
+		},
+		{
+			"default shape",
+			`{"features": {"pair": {"type": "FixedLenFeature", "dtype": "int64", "shape": [2], "default_value": [1, 2, 3]}}}`,
+			`features["pair"]: default_value has 3 values, shape [2] needs 2`,
+		},
+		{
+			"default dtype",
+			`{"features": {"age": {"type": "FixedLenFeature", "dtype": "int64", "default_value": 1.5}}}`,
+			`features["age"]: default_value 1.5 is not int64`,
+		},
+		{
+			"sequence in context",
+			`{"context_features": {"movies": {"type": "FixedLenSequenceFeature", "dtype": "string"}}}`,
+			`context_features["movies"]: unsupported type "FixedLenSequenceFeature"`,
+		},
+		{
+			"sparse size",
+			`{"features": {"rating": {"type": "SparseFeature", "dtype": "float32", "index_key": ["id"], "value_key": "value"}}}`,
+			`features["rating"]: size has 0 dimensions, index_key 1`,
+		},
+	}
+	for _, tt := range tests {
+		_, err := Read(bytes.NewBufferString(tt.json))
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Errorf("%s: got error %v, want %q", tt.desc, err, tt.err)
+		}
+	}
+}
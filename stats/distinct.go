@@ -0,0 +1,181 @@
+package stats
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// hllPrecision is the number of hash bits selecting a HyperLogLog register,
+// for a standard error of about 1.6%.
+const hllPrecision = 12
+
+// HyperLogLog estimates the number of distinct values added to it.
+type HyperLogLog struct {
+	registers []uint8
+}
+
+// NewHyperLogLog returns an empty HyperLogLog.
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{registers: make([]uint8, 1<<hllPrecision)}
+}
+
+// Add adds a value.
+func (h *HyperLogLog) Add(value []byte) {
+	x := hash(value)
+	register := x >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1)) + 1)
+	if rank > h.registers[register] {
+		h.registers[register] = rank
+	}
+}
+
+// Merge adds the values of o.
+func (h *HyperLogLog) Merge(o *HyperLogLog) {
+	for i, rank := range o.registers {
+		if rank > h.registers[i] {
+			h.registers[i] = rank
+		}
+	}
+}
+
+// Estimate returns the estimated number of distinct values.
+func (h *HyperLogLog) Estimate() int64 {
+	m := float64(len(h.registers))
+	sum, zeros := 0.0, 0
+	for _, rank := range h.registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Linear counting is more accurate for small cardinalities.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int64(estimate + 0.5)
+}
+
+// hash hashes value with FNV-1a, mixed with the SplitMix64 finalizer for
+// well distributed high bits.
+func hash(value []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(value)
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// topKCapacity is the number of values a TopK tracks. It is pruned back to
+// this size whenever it holds twice as many.
+const topKCapacity = 1000
+
+// MaxTopValues is the largest number of most frequent values a TopK
+// reports.
+const MaxTopValues = topKCapacity
+
+// maxTopValueLen is the length of the longest values a TopK counts. Longer
+// values, such as encoded images, are rarely repeated and would take up
+// memory for nothing.
+const maxTopValueLen = 1024
+
+// sampleLen is the length of the prefix of values a TopK keeps to report
+// them.
+const sampleLen = 64
+
+// topEntry is the count of a value and the start of it.
+type topEntry struct {
+	sample    string
+	truncated bool
+	count     int64
+}
+
+// TopK counts the most frequent values, up to maxTopValueLen bytes long,
+// keyed by their hash and keeping only their first sampleLen bytes. Counts
+// are exact until more than topKCapacity distinct values are seen; from then
+// on values that are pruned lose their count, so infrequent values are
+// undercounted.
+type TopK struct {
+	counts map[uint64]*topEntry
+}
+
+// NewTopK returns an empty TopK.
+func NewTopK() *TopK {
+	return &TopK{counts: map[uint64]*topEntry{}}
+}
+
+// Add counts a value, unless it is longer than maxTopValueLen.
+func (t *TopK) Add(value []byte) {
+	if len(value) > maxTopValueLen {
+		return
+	}
+	h := hash(value)
+	e, ok := t.counts[h]
+	if !ok {
+		e = &topEntry{sample: string(value[:min(len(value), sampleLen)]), truncated: len(value) > sampleLen}
+		t.counts[h] = e
+	}
+	e.count++
+	if len(t.counts) >= 2*topKCapacity {
+		t.prune()
+	}
+}
+
+// Merge adds the counts of o.
+func (t *TopK) Merge(o *TopK) {
+	for h, oe := range o.counts {
+		if e, ok := t.counts[h]; ok {
+			e.count += oe.count
+		} else {
+			e := *oe
+			t.counts[h] = &e
+		}
+	}
+	if len(t.counts) >= 2*topKCapacity {
+		t.prune()
+	}
+}
+
+func (t *TopK) prune() {
+	for _, h := range t.sorted()[topKCapacity:] {
+		delete(t.counts, h)
+	}
+}
+
+// sorted returns the hashes of the values, most frequent first.
+func (t *TopK) sorted() []uint64 {
+	hashes := make([]uint64, 0, len(t.counts))
+	for h := range t.counts {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		a, b := t.counts[hashes[i]], t.counts[hashes[j]]
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		if a.sample != b.sample {
+			return a.sample < b.sample
+		}
+		return hashes[i] < hashes[j]
+	})
+	return hashes
+}
+
+// Top returns the k most frequent values, most frequent first, at most
+// MaxTopValues of them.
+func (t *TopK) Top(k int) []ValueCount {
+	hashes := t.sorted()
+	hashes = hashes[:max(0, min(k, MaxTopValues, len(hashes)))]
+	top := make([]ValueCount, len(hashes))
+	for i, h := range hashes {
+		e := t.counts[h]
+		top[i] = ValueCount{Value: e.sample, Count: e.count, Truncated: e.truncated}
+	}
+	return top
+}
@@ -0,0 +1,228 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Distance metrics.
+const (
+	LInfinity     = "L-infinity"
+	JensenShannon = "Jensen-Shannon"
+)
+
+// histogramBuckets is the number of buckets of the numeric histograms
+// compared with Jensen-Shannon divergence.
+const histogramBuckets = 10
+
+// Thresholds bound the differences between two datasets. A zero threshold
+// is not checked.
+type Thresholds struct {
+	// LInfinity bounds the largest difference in frequency of a bytes value.
+	LInfinity float64
+	// JensenShannon bounds the divergence of numeric histograms.
+	JensenShannon float64
+	// Coverage bounds the difference in the fraction of records holding a
+	// feature.
+	Coverage float64
+}
+
+// FeatureDrift compares a feature between a baseline and a target dataset.
+type FeatureDrift struct {
+	Name string `json:"name"`
+	// FeatureList is set for the feature lists of SequenceExamples.
+	FeatureList      bool    `json:"feature_list,omitempty"`
+	BaselineKind     string  `json:"baseline_kind"`
+	TargetKind       string  `json:"target_kind"`
+	BaselineCoverage float64 `json:"baseline_coverage"`
+	TargetCoverage   float64 `json:"target_coverage"`
+	// Metric is the distance metric used, if the distributions could be
+	// compared.
+	Metric   string `json:"metric,omitempty"`
+	Distance Float  `json:"distance"`
+	// Anomalies describes the thresholds exceeded.
+	Anomalies []string `json:"anomalies,omitempty"`
+}
+
+// Drift compares the features of target to those of baseline: bytes
+// features by the L-infinity distance of their value frequencies, numeric
+// features by the Jensen-Shannon divergence, in bits, of histograms over
+// the baseline deciles. Value frequencies are those of the most frequent
+// values tracked.
+func Drift(baseline, target *Builder, t Thresholds) []*FeatureDrift {
+	drifts := compare(baseline.features, target.features, baseline.records, target.records, t)
+	lists := compare(baseline.featureLists, target.featureLists, baseline.records, target.records, t)
+	for _, d := range lists {
+		d.FeatureList = true
+	}
+	return append(drifts, lists...)
+}
+
+func compare(baseline, target map[string]*accumulator, baselineRecords, targetRecords int, t Thresholds) []*FeatureDrift {
+	names := map[string]bool{}
+	for name := range baseline {
+		names[name] = true
+	}
+	for name := range target {
+		names[name] = true
+	}
+
+	var drifts []*FeatureDrift
+	for name := range names {
+		a, b := baseline[name], target[name]
+		if a == nil {
+			a = newAccumulator()
+		}
+		if b == nil {
+			b = newAccumulator()
+		}
+		d := &FeatureDrift{
+			Name:             name,
+			BaselineKind:     mostCommon(a.kinds),
+			TargetKind:       mostCommon(b.kinds),
+			BaselineCoverage: coverage(a, baselineRecords),
+			TargetCoverage:   coverage(b, targetRecords),
+		}
+		switch {
+		case d.BaselineKind == "" || d.TargetKind == "":
+		case d.BaselineKind != d.TargetKind:
+			d.Anomalies = append(d.Anomalies, fmt.Sprintf("kind changed from %s to %s", d.BaselineKind, d.TargetKind))
+		case a.top != nil && b.top != nil:
+			d.Metric = LInfinity
+			d.Distance = Float(lInfinity(a, b))
+			if t.LInfinity > 0 && float64(d.Distance) > t.LInfinity {
+				d.Anomalies = append(d.Anomalies, fmt.Sprintf("L-infinity distance %.3g above %g", float64(d.Distance), t.LInfinity))
+			}
+		case a.numeric != nil && b.numeric != nil:
+			d.Metric = JensenShannon
+			d.Distance = Float(jensenShannon(a, b))
+			if t.JensenShannon > 0 && float64(d.Distance) > t.JensenShannon {
+				d.Anomalies = append(d.Anomalies, fmt.Sprintf("Jensen-Shannon divergence %.3g above %g", float64(d.Distance), t.JensenShannon))
+			}
+		}
+		if diff := math.Abs(d.BaselineCoverage - d.TargetCoverage); t.Coverage > 0 && diff > t.Coverage {
+			d.Anomalies = append(d.Anomalies, fmt.Sprintf("coverage changed by %.1f%%", 100*diff))
+		}
+		drifts = append(drifts, d)
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Name < drifts[j].Name })
+	return drifts
+}
+
+func coverage(a *accumulator, records int) float64 {
+	if records == 0 {
+		return 0
+	}
+	return float64(a.present) / float64(records)
+}
+
+// lInfinity returns the largest difference in frequency of a value.
+func lInfinity(a, b *accumulator) float64 {
+	frequencies := func(acc *accumulator) map[uint64]float64 {
+		f := map[uint64]float64{}
+		for h, e := range acc.top.counts {
+			f[h] = float64(e.count) / float64(acc.bytes)
+		}
+		return f
+	}
+	fa, fb := frequencies(a), frequencies(b)
+	max := 0.0
+	for value, p := range fa {
+		max = math.Max(max, math.Abs(p-fb[value]))
+	}
+	for value, q := range fb {
+		if _, ok := fa[value]; !ok {
+			max = math.Max(max, q)
+		}
+	}
+	return max
+}
+
+// jensenShannon returns the Jensen-Shannon divergence of the histograms of
+// a and b over buckets bounded by the deciles of a, with NaN in a bucket of
+// its own.
+func jensenShannon(a, b *accumulator) float64 {
+	var edges []float64
+	for i := 1; i < histogramBuckets; i++ {
+		edge := a.numeric.Quantile(float64(i) / histogramBuckets)
+		if len(edges) == 0 || edge > edges[len(edges)-1] {
+			edges = append(edges, edge)
+		}
+	}
+	p, q := histogram(a, edges), histogram(b, edges)
+	divergence := 0.0
+	for i := range p {
+		m := (p[i] + q[i]) / 2
+		divergence += (entropyTerm(p[i], m) + entropyTerm(q[i], m)) / 2
+	}
+	return divergence
+}
+
+// histogram returns the fraction of values of a in each bucket bounded by
+// edges, followed by the fraction of NaN.
+func histogram(a *accumulator, edges []float64) []float64 {
+	total := float64(a.n + a.nan)
+	if total == 0 {
+		return make([]float64, len(edges)+2)
+	}
+	numbers := float64(a.n) / total
+	h := make([]float64, 0, len(edges)+2)
+	below := 0.0
+	for _, edge := range edges {
+		cdf := a.numeric.CDF(edge)
+		h = append(h, (cdf-below)*numbers)
+		below = cdf
+	}
+	h = append(h, (1-below)*numbers)
+	return append(h, float64(a.nan)/total)
+}
+
+func entropyTerm(p, m float64) float64 {
+	if p == 0 {
+		return 0
+	}
+	return p * math.Log2(p/m)
+}
+
+// WriteDrift writes a table comparing every feature, followed by the
+// anomalies found.
+func WriteDrift(w io.Writer, drifts []*FeatureDrift) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "feature\tkind\tcoverage\tmetric\tdistance\tanomalies")
+	anomalies := 0
+	for _, d := range drifts {
+		name := d.Name
+		if d.FeatureList {
+			name += " (feature list)"
+		}
+		kind := d.BaselineKind
+		if d.TargetKind != d.BaselineKind {
+			kind = orNone(d.BaselineKind) + " -> " + orNone(d.TargetKind)
+		}
+		distance := "-"
+		if d.Metric != "" {
+			distance = fmt.Sprintf("%.4f", float64(d.Distance))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.1f%% -> %.1f%%\t%s\t%s\t%s\n", name, kind,
+			100*d.BaselineCoverage, 100*d.TargetCoverage, orNone(d.Metric), distance, strings.Join(d.Anomalies, "; "))
+		if len(d.Anomalies) > 0 {
+			anomalies++
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d of %d features drifted\n", anomalies, len(drifts))
+	return err
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,190 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// sketchK bounds the size of a Sketch: about 3k values are kept, for a rank
+// error of roughly 1.65/k.
+const sketchK = 200
+
+// Sketch is a mergeable quantile sketch, a KLL sketch with deterministic
+// compaction. Values at level i of the sketch stand for 2^i values added.
+// Count, Min, Max and Sum are exact.
+type Sketch struct {
+	levels [][]float64
+	count  int64
+	min    float64
+	max    float64
+	sum    float64
+	// odd alternates the half of each compaction that is kept.
+	odd bool
+}
+
+// Add adds a value to the sketch. NaN is ignored.
+func (s *Sketch) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	if s.count == 0 || x < s.min {
+		s.min = x
+	}
+	if s.count == 0 || x > s.max {
+		s.max = x
+	}
+	s.count++
+	s.sum += x
+	if len(s.levels) == 0 {
+		s.levels = make([][]float64, 1)
+	}
+	s.levels[0] = append(s.levels[0], x)
+	s.compress()
+}
+
+// Merge adds the values of o to the sketch.
+func (s *Sketch) Merge(o *Sketch) {
+	if o.count == 0 {
+		return
+	}
+	if s.count == 0 || o.min < s.min {
+		s.min = o.min
+	}
+	if s.count == 0 || o.max > s.max {
+		s.max = o.max
+	}
+	s.count += o.count
+	s.sum += o.sum
+	for len(s.levels) < len(o.levels) {
+		s.levels = append(s.levels, nil)
+	}
+	for level, items := range o.levels {
+		s.levels[level] = append(s.levels[level], items...)
+	}
+	s.compress()
+}
+
+// capacity returns the number of values kept at a level before compacting
+// it. Lower levels get exponentially less room.
+func (s *Sketch) capacity(level int) int {
+	depth := len(s.levels) - 1 - level
+	c := int(math.Ceil(sketchK * math.Pow(2.0/3, float64(depth))))
+	if c < 2 {
+		return 2
+	}
+	return c
+}
+
+// compress halves every level over capacity, promoting every other sorted
+// value to the level above.
+func (s *Sketch) compress() {
+	for level := 0; level < len(s.levels); level++ {
+		items := s.levels[level]
+		if len(items) <= s.capacity(level) {
+			continue
+		}
+		if level+1 == len(s.levels) {
+			s.levels = append(s.levels, nil)
+		}
+		sort.Float64s(items)
+		var kept []float64
+		if len(items)%2 == 1 {
+			kept = append(kept, items[len(items)-1])
+			items = items[:len(items)-1]
+		}
+		start := 0
+		if s.odd {
+			start = 1
+		}
+		s.odd = !s.odd
+		for i := start; i < len(items); i += 2 {
+			s.levels[level+1] = append(s.levels[level+1], items[i])
+		}
+		s.levels[level] = kept
+	}
+}
+
+type weighted struct {
+	value  float64
+	weight int64
+}
+
+// items returns the values of the sketch with their weights, sorted.
+func (s *Sketch) items() []weighted {
+	var items []weighted
+	for level, values := range s.levels {
+		for _, x := range values {
+			items = append(items, weighted{x, 1 << uint(level)})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].value < items[j].value })
+	return items
+}
+
+// Count returns the number of values added.
+func (s *Sketch) Count() int64 {
+	return s.count
+}
+
+// Min returns the smallest value added.
+func (s *Sketch) Min() float64 {
+	return s.min
+}
+
+// Max returns the largest value added.
+func (s *Sketch) Max() float64 {
+	return s.max
+}
+
+// Mean returns the mean of the values added.
+func (s *Sketch) Mean() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.sum / float64(s.count)
+}
+
+// Quantile returns an approximation of the value of rank q, between 0 and 1.
+func (s *Sketch) Quantile(q float64) float64 {
+	switch {
+	case s.count == 0:
+		return math.NaN()
+	case q <= 0:
+		return s.min
+	case q >= 1:
+		return s.max
+	}
+	items := s.items()
+	var total int64
+	for _, item := range items {
+		total += item.weight
+	}
+	target := q * float64(total)
+	var cumulative int64
+	for _, item := range items {
+		cumulative += item.weight
+		if float64(cumulative) >= target {
+			return item.value
+		}
+	}
+	return s.max
+}
+
+// CDF returns an approximation of the fraction of values less than or equal
+// to x.
+func (s *Sketch) CDF(x float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	var below, total int64
+	for level, values := range s.levels {
+		weight := int64(1) << uint(level)
+		for _, v := range values {
+			if v <= x {
+				below += weight
+			}
+			total += weight
+		}
+	}
+	return float64(below) / float64(total)
+}
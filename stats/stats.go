@@ -0,0 +1,464 @@
+// Package stats computes descriptive statistics of the features of Examples
+// and SequenceExamples in a single streaming pass, with mergeable partial
+// results so that shards can be processed in parallel.
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/schema"
+	"github.com/emla2805/tfr/utils"
+	"google.golang.org/protobuf/proto"
+)
+
+// QuantileRanks are the ranks of the quantiles reported for numeric values.
+var QuantileRanks = []float64{0, 0.01, 0.05, 0.25, 0.5, 0.75, 0.95, 0.99, 1}
+
+// DefaultTopValues is the number of most frequent bytes values a Builder
+// reports by default.
+const DefaultTopValues = 10
+
+// Float is a float64 encoding infinities and NaN as the JSON strings
+// "Infinity", "-Infinity" and "NaN", like protojson does.
+type Float float64
+
+// MarshalJSON implements json.Marshaler.
+func (f Float) MarshalJSON() ([]byte, error) {
+	x := float64(f)
+	switch {
+	case math.IsNaN(x):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(x, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(x, -1):
+		return []byte(`"-Infinity"`), nil
+	}
+	return strconv.AppendFloat(nil, x, 'g', -1, 64), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Float) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		x, err := strconv.ParseFloat(s, 64)
+		*f = Float(x)
+		return err
+	}
+	var x float64
+	err := json.Unmarshal(data, &x)
+	*f = Float(x)
+	return err
+}
+
+// Quantile is the approximate value of a rank between 0 and 1.
+type Quantile struct {
+	Rank  float64 `json:"rank"`
+	Value Float   `json:"value"`
+}
+
+// Distribution summarises a distribution of counts.
+type Distribution struct {
+	Min       Float      `json:"min"`
+	Max       Float      `json:"max"`
+	Mean      Float      `json:"mean"`
+	Quantiles []Quantile `json:"quantiles"`
+}
+
+// Numeric summarises the int64 and float values of a feature.
+type Numeric struct {
+	Count     int64      `json:"count"`
+	Min       Float      `json:"min"`
+	Max       Float      `json:"max"`
+	Mean      Float      `json:"mean"`
+	Stddev    Float      `json:"stddev"`
+	Zeros     int64      `json:"zeros"`
+	NaN       int64      `json:"nan"`
+	Quantiles []Quantile `json:"quantiles"`
+}
+
+// ValueCount is a bytes value and the number of times it was seen.
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+	// Truncated is set when Value is only the start of the value.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Bytes summarises the bytes values of a feature.
+type Bytes struct {
+	Count int64 `json:"count"`
+	// Distinct is an estimate of the number of distinct values.
+	Distinct  int64        `json:"distinct"`
+	AvgLength Float        `json:"avg_length"`
+	Top       []ValueCount `json:"top"`
+}
+
+// Feature holds the statistics of a feature. For feature lists of
+// SequenceExamples the value counts are per step.
+type Feature struct {
+	Name string `json:"name"`
+	// Kind is the kind seen most often, Kinds counts the records per kind.
+	Kind  string         `json:"kind"`
+	Kinds map[string]int `json:"kinds"`
+	// Present is the number of records holding the feature, Coverage the
+	// fraction of records.
+	Present    int           `json:"present"`
+	Coverage   float64       `json:"coverage"`
+	ValueCount *Distribution `json:"value_count"`
+	Steps      *Distribution `json:"steps,omitempty"`
+	Numeric    *Numeric      `json:"numeric,omitempty"`
+	Bytes      *Bytes        `json:"bytes,omitempty"`
+}
+
+// Stats holds the statistics of a dataset.
+type Stats struct {
+	Records int `json:"records"`
+	// Sequence is set when the records are SequenceExamples, in which case
+	// Features holds the context features.
+	Sequence     bool       `json:"sequence"`
+	Features     []*Feature `json:"features"`
+	FeatureLists []*Feature `json:"feature_lists,omitempty"`
+}
+
+// accumulator gathers the statistics of a feature.
+type accumulator struct {
+	kinds   map[string]int
+	present int
+	counts  Sketch
+	steps   *Sketch
+
+	numeric *Sketch
+	// n, mean and m2 are the running moments of the numeric values,
+	// following Welford.
+	n, zeros, nan int64
+	mean, m2      float64
+
+	bytes    int64
+	length   int64
+	top      *TopK
+	distinct *HyperLogLog
+}
+
+func newAccumulator() *accumulator {
+	return &accumulator{kinds: map[string]int{}}
+}
+
+func (a *accumulator) addNumber(x float64) {
+	if a.numeric == nil {
+		a.numeric = &Sketch{}
+	}
+	if math.IsNaN(x) {
+		a.nan++
+		return
+	}
+	if x == 0 {
+		a.zeros++
+	}
+	a.numeric.Add(x)
+	a.n++
+	delta := x - a.mean
+	a.mean += delta / float64(a.n)
+	a.m2 += delta * (x - a.mean)
+}
+
+func (a *accumulator) addBytes(b []byte) {
+	if a.top == nil {
+		a.top = NewTopK()
+		a.distinct = NewHyperLogLog()
+	}
+	a.bytes++
+	a.length += int64(len(b))
+	a.top.Add(b)
+	a.distinct.Add(b)
+}
+
+// addValues adds the values of f, returning its kind.
+func (a *accumulator) addValues(f *protobuf.Feature) string {
+	kind, count := schema.Kind(f)
+	a.counts.Add(float64(count))
+	for _, x := range f.GetInt64List().GetValue() {
+		a.addNumber(float64(x))
+	}
+	for _, x := range f.GetFloatList().GetValue() {
+		a.addNumber(float64(x))
+	}
+	for _, b := range f.GetBytesList().GetValue() {
+		a.addBytes(b)
+	}
+	return kind
+}
+
+// addView adds the values of f, decoded into the buffers of s, returning
+// its kind.
+func (a *accumulator) addView(f utils.FeatureView, s *scratch) (string, error) {
+	var err error
+	switch f.Kind() {
+	case schema.KindInt64:
+		if s.ints, err = f.AppendInt64s(s.ints[:0]); err != nil {
+			return "", err
+		}
+		a.counts.Add(float64(len(s.ints)))
+		for _, x := range s.ints {
+			a.addNumber(float64(x))
+		}
+		return schema.KindInt64, nil
+	case schema.KindFloat:
+		if s.floats, err = f.AppendFloats(s.floats[:0]); err != nil {
+			return "", err
+		}
+		a.counts.Add(float64(len(s.floats)))
+		for _, x := range s.floats {
+			a.addNumber(float64(x))
+		}
+		return schema.KindFloat, nil
+	case "none":
+		// Reported as an empty bytes list, as schema.Kind does.
+		a.counts.Add(0)
+		return schema.KindBytes, nil
+	}
+	if s.bytes, err = f.AppendBytes(s.bytes[:0]); err != nil {
+		return "", err
+	}
+	a.counts.Add(float64(len(s.bytes)))
+	for _, b := range s.bytes {
+		a.addBytes(b)
+	}
+	clear(s.bytes)
+	return schema.KindBytes, nil
+}
+
+// scratch holds the values of a feature added from a view, reused from one
+// feature to the next.
+type scratch struct {
+	ints   []int64
+	floats []float32
+	bytes  [][]byte
+}
+
+func (a *accumulator) merge(o *accumulator) {
+	for kind, n := range o.kinds {
+		a.kinds[kind] += n
+	}
+	a.present += o.present
+	a.counts.Merge(&o.counts)
+	if o.steps != nil {
+		if a.steps == nil {
+			a.steps = &Sketch{}
+		}
+		a.steps.Merge(o.steps)
+	}
+
+	if o.numeric != nil {
+		if a.numeric == nil {
+			a.numeric = &Sketch{}
+		}
+		a.numeric.Merge(o.numeric)
+		// Chan et al.'s parallel update of the moments.
+		n := a.n + o.n
+		if n > 0 {
+			delta := o.mean - a.mean
+			a.m2 += o.m2 + delta*delta*float64(a.n)*float64(o.n)/float64(n)
+			a.mean += delta * float64(o.n) / float64(n)
+		}
+		a.n = n
+		a.zeros += o.zeros
+		a.nan += o.nan
+	}
+
+	if o.top != nil {
+		if a.top == nil {
+			a.top = NewTopK()
+			a.distinct = NewHyperLogLog()
+		}
+		a.bytes += o.bytes
+		a.length += o.length
+		a.top.Merge(o.top)
+		a.distinct.Merge(o.distinct)
+	}
+}
+
+// Builder computes Stats from records added one at a time. Builders fed
+// with different shards can be merged.
+type Builder struct {
+	// TopValues is the number of most frequent bytes values reported, at
+	// most MaxTopValues. Values longer than 1KB are not counted.
+	TopValues int
+
+	records      int
+	sequence     bool
+	features     map[string]*accumulator
+	featureLists map[string]*accumulator
+	scratch      scratch
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		TopValues:    DefaultTopValues,
+		features:     map[string]*accumulator{},
+		featureLists: map[string]*accumulator{},
+	}
+}
+
+func accumulatorOf(accumulators map[string]*accumulator, name string) *accumulator {
+	a, ok := accumulators[name]
+	if !ok {
+		a = newAccumulator()
+		accumulators[name] = a
+	}
+	return a
+}
+
+// Add adds a protobuf.Example or protobuf.SequenceExample.
+func (b *Builder) Add(m proto.Message) {
+	b.records++
+	switch m := m.(type) {
+	case *protobuf.Example:
+		b.addFeatures(m.GetFeatures())
+	case *protobuf.SequenceExample:
+		b.sequence = true
+		b.addFeatures(m.GetContext())
+		for name, list := range m.GetFeatureLists().GetFeatureList() {
+			a := accumulatorOf(b.featureLists, name)
+			if a.steps == nil {
+				a.steps = &Sketch{}
+			}
+			a.steps.Add(float64(len(list.GetFeature())))
+			kinds := map[string]bool{}
+			for _, step := range list.GetFeature() {
+				kinds[a.addValues(step)] = true
+			}
+			for kind := range kinds {
+				a.kinds[kind]++
+			}
+			a.present++
+		}
+	}
+}
+
+// AddView adds the Example viewed by v, decoding its values without
+// materializing it.
+func (b *Builder) AddView(v *utils.ExampleView) error {
+	b.records++
+	for name, f := range v.All() {
+		a := accumulatorOf(b.features, name)
+		kind, err := a.addView(f, &b.scratch)
+		if err != nil {
+			return err
+		}
+		a.kinds[kind]++
+		a.present++
+	}
+	return nil
+}
+
+func (b *Builder) addFeatures(fs *protobuf.Features) {
+	for name, f := range fs.GetFeature() {
+		a := accumulatorOf(b.features, name)
+		a.kinds[a.addValues(f)]++
+		a.present++
+	}
+}
+
+// Merge adds the records added to o.
+func (b *Builder) Merge(o *Builder) {
+	b.records += o.records
+	b.sequence = b.sequence || o.sequence
+	for name, a := range o.features {
+		accumulatorOf(b.features, name).merge(a)
+	}
+	for name, a := range o.featureLists {
+		accumulatorOf(b.featureLists, name).merge(a)
+	}
+}
+
+// Stats returns the statistics of the records added so far, with features
+// sorted by name.
+func (b *Builder) Stats() *Stats {
+	return &Stats{
+		Records:      b.records,
+		Sequence:     b.sequence,
+		Features:     b.summarize(b.features),
+		FeatureLists: b.summarize(b.featureLists),
+	}
+}
+
+func (b *Builder) summarize(accumulators map[string]*accumulator) []*Feature {
+	features := make([]*Feature, 0, len(accumulators))
+	for name, a := range accumulators {
+		f := &Feature{
+			Name:       name,
+			Kind:       mostCommon(a.kinds),
+			Kinds:      a.kinds,
+			Present:    a.present,
+			ValueCount: distribution(&a.counts),
+		}
+		if b.records > 0 {
+			f.Coverage = float64(a.present) / float64(b.records)
+		}
+		if a.steps != nil {
+			f.Steps = distribution(a.steps)
+		}
+		if a.numeric != nil {
+			f.Numeric = &Numeric{
+				Count:     a.n,
+				Min:       Float(a.numeric.Min()),
+				Max:       Float(a.numeric.Max()),
+				Mean:      Float(a.mean),
+				Zeros:     a.zeros,
+				NaN:       a.nan,
+				Quantiles: quantiles(a.numeric),
+			}
+			if a.n > 0 {
+				f.Numeric.Stddev = Float(math.Sqrt(a.m2 / float64(a.n)))
+			}
+		}
+		if a.top != nil {
+			f.Bytes = &Bytes{
+				Count:    a.bytes,
+				Distinct: a.distinct.Estimate(),
+				Top:      a.top.Top(b.TopValues),
+			}
+			if a.bytes > 0 {
+				f.Bytes.AvgLength = Float(float64(a.length) / float64(a.bytes))
+			}
+		}
+		features = append(features, f)
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i].Name < features[j].Name })
+	return features
+}
+
+func distribution(s *Sketch) *Distribution {
+	return &Distribution{
+		Min:       Float(s.Min()),
+		Max:       Float(s.Max()),
+		Mean:      Float(s.Mean()),
+		Quantiles: quantiles(s),
+	}
+}
+
+func quantiles(s *Sketch) []Quantile {
+	if s.Count() == 0 {
+		return nil
+	}
+	list := make([]Quantile, len(QuantileRanks))
+	for i, q := range QuantileRanks {
+		list[i] = Quantile{Rank: q, Value: Float(s.Quantile(q))}
+	}
+	return list
+}
+
+func mostCommon(kinds map[string]int) string {
+	best := ""
+	for kind, n := range kinds {
+		if best == "" || n > kinds[best] || n == kinds[best] && kind < best {
+			best = kind
+		}
+	}
+	return best
+}
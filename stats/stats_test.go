@@ -0,0 +1,226 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/utils"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSketch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	whole := &Sketch{}
+	parts := []*Sketch{{}, {}, {}}
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()
+		whole.Add(x)
+		parts[i%3].Add(x)
+	}
+	merged := &Sketch{}
+	for _, part := range parts {
+		merged.Merge(part)
+	}
+
+	for _, s := range []*Sketch{whole, merged} {
+		if s.Count() != 100000 {
+			t.Errorf("got count %d, want 100000", s.Count())
+		}
+		for _, q := range []float64{0.01, 0.25, 0.5, 0.75, 0.99} {
+			if got := s.Quantile(q); math.Abs(got-q) > 0.02 {
+				t.Errorf("quantile %v: got %v", q, got)
+			}
+			if got := s.CDF(q); math.Abs(got-q) > 0.02 {
+				t.Errorf("cdf %v: got %v", q, got)
+			}
+		}
+	}
+	if whole.Min() != merged.Min() || whole.Max() != merged.Max() {
+		t.Errorf("got min %v max %v merged, want %v %v", merged.Min(), merged.Max(), whole.Min(), whole.Max())
+	}
+}
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		a, b := NewHyperLogLog(), NewHyperLogLog()
+		for i := 0; i < n; i++ {
+			a.Add([]byte(strconv.Itoa(i)))
+			b.Add([]byte(strconv.Itoa(i / 2)))
+		}
+		a.Merge(b)
+		if got := a.Estimate(); math.Abs(float64(got)-float64(n))/float64(n) > 0.05 {
+			t.Errorf("%d distinct values: got estimate %d", n, got)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	top := NewTopK()
+	for i := 0; i < 10000; i++ {
+		top.Add([]byte(strconv.Itoa(i)))
+		if i%10 == 0 {
+			top.Add([]byte("frequent"))
+		}
+	}
+	other := NewTopK()
+	other.Add([]byte("rare"))
+	other.Add([]byte("frequent"))
+	top.Merge(other)
+
+	got := top.Top(1)
+	if len(got) != 1 || got[0] != (ValueCount{Value: "frequent", Count: 1001}) {
+		t.Errorf("got %v, want frequent 1001", got)
+	}
+
+	// Long values are reported by their start, and blobs are not counted.
+	long, blob := bytes.Repeat([]byte("a"), 100), bytes.Repeat([]byte("b"), 2000)
+	top = NewTopK()
+	for i := 0; i < 3; i++ {
+		top.Add(long)
+		top.Add(blob)
+	}
+	want := []ValueCount{{Value: string(long[:sampleLen]), Count: 3, Truncated: true}}
+	if got := top.Top(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := top.Top(-1); len(got) != 0 {
+		t.Errorf("got %v for a negative number of values", got)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	records := []*protobuf.Example{
+		protobuf.NewExample().
+			Int64("age", 20).
+			Strings("movie", "Heat", "Alien").
+			Float("rating", 0, float32(math.NaN())).
+			Build(),
+		protobuf.NewExample().
+			Int64("age", 40).
+			Strings("movie", "Heat").
+			Build(),
+		protobuf.NewExample().
+			Int64("age", 30).
+			Strings("movie", "Heat", "Up", "Jaws").
+			Float("rating", 8).
+			Build(),
+	}
+
+	// Stats merged from shards, or of records viewed rather than decoded,
+	// are the same as those of a single pass.
+	whole, merged, viewed := NewBuilder(), NewBuilder(), NewBuilder()
+	for _, shard := range [][]*protobuf.Example{records[:1], records[1:]} {
+		b := NewBuilder()
+		for _, m := range shard {
+			whole.Add(m)
+			b.Add(m)
+			data, err := proto.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			view, err := utils.NewExampleView(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := viewed.AddView(view); err != nil {
+				t.Fatal(err)
+			}
+		}
+		merged.Merge(b)
+	}
+
+	for _, b := range []*Builder{whole, merged, viewed} {
+		var buf bytes.Buffer
+		if err := WriteText(&buf, b.Stats()); err != nil {
+			t.Fatal(err)
+		}
+		want := `3 examples
+
+numeric features
+feature  kind   coverage  values     mean  stddev  zeros  nan  min  median  max
+age      int64  100.0%    1          30    8.165   0      0    20   30      40
+rating   float  66.7%     1-2 (1.5)  4     4       1      1    0    0       8
+
+bytes features
+feature  coverage  values     distinct  avg length  top values
+movie    100.0%    1-3 (2.0)  ~4        3.8         "Heat" 50.0%, "Alien" 16.7%, "Jaws" 16.7%
+`
+		if got := buf.String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	}
+
+	whole.TopValues = 1
+	if top := whole.Stats().Features[1].Bytes.Top; len(top) != 1 || top[0] != (ValueCount{Value: "Heat", Count: 3}) {
+		t.Errorf("got top values %+v, want Heat only", top)
+	}
+	whole.TopValues = -1
+	if top := whole.Stats().Features[1].Bytes.Top; len(top) != 0 {
+		t.Errorf("got top values %+v for a negative number", top)
+	}
+}
+
+func TestFloatJSON(t *testing.T) {
+	for _, x := range []float64{1.5, math.Inf(1), math.Inf(-1)} {
+		data, err := Float(x).MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var f Float
+		if err := f.UnmarshalJSON(data); err != nil || float64(f) != x {
+			t.Errorf("%v: got %v, %v from %s", x, f, err, data)
+		}
+	}
+	if data, _ := Float(math.NaN()).MarshalJSON(); !strings.Contains(string(data), "NaN") {
+		t.Errorf("got %s for NaN", data)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	baseline, same, shifted := NewBuilder(), NewBuilder(), NewBuilder()
+	for i := 0; i < 5000; i++ {
+		movies := []string{"Heat", "Alien", "Up", "Jaws"}
+		baseline.Add(protobuf.NewExample().
+			Int64("age", int64(18+r.Intn(60))).
+			Strings("movie", movies[r.Intn(4)]).
+			Int64("label", int64(r.Intn(2))).
+			Build())
+		same.Add(protobuf.NewExample().
+			Int64("age", int64(18+r.Intn(60))).
+			Strings("movie", movies[r.Intn(4)]).
+			Int64("label", int64(r.Intn(2))).
+			Build())
+		b := protobuf.NewExample().Int64("age", int64(50+r.Intn(60)))
+		if movie := movies[r.Intn(2)]; i%2 != 0 {
+			b.Strings("movie", movie)
+		}
+		shifted.Add(b.Float("label", float32(r.Intn(2))).Build())
+	}
+	thresholds := Thresholds{LInfinity: 0.1, JensenShannon: 0.1, Coverage: 0.1}
+
+	for _, d := range Drift(baseline, same, thresholds) {
+		if len(d.Anomalies) > 0 {
+			t.Errorf("%s: got anomalies %v for the same distribution", d.Name, d.Anomalies)
+		}
+	}
+
+	var got []string
+	for _, d := range Drift(baseline, shifted, thresholds) {
+		got = append(got, d.Name+": "+strings.Join(d.Anomalies, "; "))
+	}
+	want := []string{
+		"age: Jensen-Shannon divergence 0.427 above 0.1",
+		"label: kind changed from int64 to float",
+		"movie: L-infinity distance 0.26 above 0.1; coverage changed by 50.0%",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// topShown is the number of top values shown per feature by WriteText.
+const topShown = 3
+
+// WriteText writes a human-readable report of s, with a table of numeric
+// and one of bytes features.
+func WriteText(w io.Writer, s *Stats) error {
+	if !s.Sequence {
+		fmt.Fprintf(w, "%d examples\n", s.Records)
+		return writeTables(w, s.Features, false)
+	}
+	fmt.Fprintf(w, "%d sequence examples\n\ncontext\n", s.Records)
+	if err := writeTables(w, s.Features, false); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nfeature lists")
+	return writeTables(w, s.FeatureLists, true)
+}
+
+func writeTables(w io.Writer, features []*Feature, sequence bool) error {
+	numeric := [][]string{{"feature", "kind", "coverage", "values", "mean", "stddev", "zeros", "nan", "min", "median", "max"}}
+	bytes := [][]string{{"feature", "coverage", "values", "distinct", "avg length", "top values"}}
+	if sequence {
+		numeric[0] = insert(numeric[0], 3, "steps")
+		bytes[0] = insert(bytes[0], 2, "steps")
+	}
+
+	for _, f := range features {
+		if n := f.Numeric; n != nil {
+			row := []string{
+				f.Name, f.Kind, percent(f.Coverage), counts(f.ValueCount),
+				number(n.Mean), number(n.Stddev), strconv.FormatInt(n.Zeros, 10), strconv.FormatInt(n.NaN, 10),
+				number(n.Min), number(quantile(n.Quantiles, 0.5)), number(n.Max),
+			}
+			if sequence {
+				row = insert(row, 3, counts(f.Steps))
+			}
+			numeric = append(numeric, row)
+		}
+		if b := f.Bytes; b != nil {
+			row := []string{
+				f.Name, percent(f.Coverage), counts(f.ValueCount),
+				"~" + strconv.FormatInt(b.Distinct, 10), fmt.Sprintf("%.1f", float64(b.AvgLength)), topValues(b),
+			}
+			if sequence {
+				row = insert(row, 2, counts(f.Steps))
+			}
+			bytes = append(bytes, row)
+		}
+	}
+
+	for _, table := range []struct {
+		title string
+		rows  [][]string
+	}{{"numeric features", numeric}, {"bytes features", bytes}} {
+		if len(table.rows) == 1 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n", table.title)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, row := range table.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insert(row []string, i int, cell string) []string {
+	row = append(row[:i], append([]string{cell}, row[i:]...)...)
+	return row
+}
+
+func percent(x float64) string {
+	return fmt.Sprintf("%.1f%%", 100*x)
+}
+
+// counts formats a count distribution as its range and mean.
+func counts(d *Distribution) string {
+	if d.Min == d.Max {
+		return number(d.Min)
+	}
+	return fmt.Sprintf("%s-%s (%.1f)", number(d.Min), number(d.Max), float64(d.Mean))
+}
+
+func number(x Float) string {
+	return strconv.FormatFloat(float64(x), 'g', 4, 64)
+}
+
+func quantile(quantiles []Quantile, rank float64) Float {
+	for _, q := range quantiles {
+		if q.Rank == rank {
+			return q.Value
+		}
+	}
+	return Float(math.NaN())
+}
+
+// topValues formats the most frequent values with their share of all
+// values.
+func topValues(b *Bytes) string {
+	var items []string
+	for i, vc := range b.Top {
+		if i == topShown {
+			break
+		}
+		value := vc.Value
+		if utf8.RuneCountInString(value) > 20 {
+			value = string([]rune(value)[:19]) + "…"
+		} else if vc.Truncated {
+			value += "…"
+		}
+		items = append(items, fmt.Sprintf("%s %.1f%%", strconv.Quote(value), 100*float64(vc.Count)/float64(b.Count)))
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GCS reads objects from Google Cloud Storage through its JSON API, naming
+// them gs://bucket/object.
+type GCS struct {
+	// Endpoint is the URL of the API, such as https://storage.googleapis.com.
+	Endpoint string
+	// Token is an OAuth 2.0 access token sent with every request, which
+	// public buckets do not need.
+	Token  string
+	Client *http.Client
+}
+
+// NewGCS returns a GCS authenticated with $GOOGLE_OAUTH_ACCESS_TOKEN, such
+// as one printed by gcloud auth print-access-token. $STORAGE_EMULATOR_HOST
+// replaces the endpoint with that of an emulator, as in the Cloud Storage
+// client libraries.
+func NewGCS() *GCS {
+	g := &GCS{Endpoint: "https://storage.googleapis.com", Token: os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")}
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		g.Endpoint = host
+		if !strings.Contains(host, "://") {
+			g.Endpoint = "http://" + host
+		}
+	}
+	return g
+}
+
+// splitURL splits a name of the form scheme://bucket/object.
+func splitURL(name, scheme string) (bucket, object string, err error) {
+	rest, ok := strings.CutPrefix(name, scheme+"://")
+	if !ok {
+		return "", "", fmt.Errorf("%s: not a %s:// URL", name, scheme)
+	}
+	bucket, object, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("%s: missing bucket", name)
+	}
+	return bucket, object, nil
+}
+
+// objectURL returns the URL of the metadata of the object name.
+func (g *GCS) objectURL(name string) (string, error) {
+	bucket, object, err := splitURL(name, "gs")
+	if err != nil {
+		return "", err
+	}
+	if object == "" {
+		return "", fmt.Errorf("%s: missing object name", name)
+	}
+	// Slashes within object names must be escaped too.
+	object = strings.ReplaceAll(url.PathEscape(object), "/", "%2F")
+	return g.Endpoint + "/storage/v1/b/" + url.PathEscape(bucket) + "/o/" + object, nil
+}
+
+func (g *GCS) authorize(req *http.Request) {
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+}
+
+func (g *GCS) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return g.ReadRange(ctx, name, 0, -1)
+}
+
+func (g *GCS) ReadRange(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error) {
+	u, err := g.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+	return readRange(ctx, g.Client, name, u+"?alt=media", offset, length, g.authorize)
+}
+
+// gcsObject is the metadata of an object. The API gives sizes as strings.
+type gcsObject struct {
+	Name string `json:"name"`
+	Size string `json:"size"`
+}
+
+func (o gcsObject) info(bucket string) (FileInfo, error) {
+	size, err := strconv.ParseInt(o.Size, 10, 64)
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("gs://%s/%s: invalid size %q", bucket, o.Name, o.Size)
+	}
+	return FileInfo{Name: "gs://" + bucket + "/" + o.Name, Size: size}, nil
+}
+
+// getJSON decodes the JSON response to a GET request of url into v.
+func (g *GCS) getJSON(ctx context.Context, name, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	g.authorize(req)
+	resp, err := send(g.Client, req, name)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+func (g *GCS) Stat(ctx context.Context, name string) (FileInfo, error) {
+	u, err := g.objectURL(name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	var object gcsObject
+	if err := g.getJSON(ctx, name, u, &object); err != nil {
+		return FileInfo{}, err
+	}
+	bucket, _, _ := splitURL(name, "gs")
+	return object.info(bucket)
+}
+
+func (g *GCS) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	bucket, objectPrefix, err := splitURL(prefix, "gs")
+	if err != nil {
+		return nil, err
+	}
+	var files []FileInfo
+	query := url.Values{"prefix": {objectPrefix}}
+	for {
+		var page struct {
+			Items         []gcsObject `json:"items"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		u := g.Endpoint + "/storage/v1/b/" + url.PathEscape(bucket) + "/o?" + query.Encode()
+		if err := g.getJSON(ctx, prefix, u, &page); err != nil {
+			return nil, err
+		}
+		for _, object := range page.Items {
+			info, err := object.info(bucket)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, info)
+		}
+		if page.NextPageToken == "" {
+			return files, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
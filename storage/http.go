@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HTTP reads files from HTTP servers, naming them by their http:// or
+// https:// URL. Servers cannot be listed.
+type HTTP struct {
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+}
+
+func (h *HTTP) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return h.ReadRange(ctx, name, 0, -1)
+}
+
+func (h *HTTP) ReadRange(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error) {
+	return readRange(ctx, h.Client, name, name, offset, length, nil)
+}
+
+func (h *HTTP) Stat(ctx context.Context, name string) (FileInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, name, nil)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	resp, err := send(h.Client, req, name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	resp.Body.Close()
+	if resp.ContentLength < 0 {
+		return FileInfo{}, fmt.Errorf("%s: unknown size", name)
+	}
+	return FileInfo{Name: name, Size: resp.ContentLength}, nil
+}
+
+func (h *HTTP) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	return nil, fmt.Errorf("%s: HTTP servers cannot be listed", prefix)
+}
+
+// StatusError reports an unsuccessful response to a request for a file.
+type StatusError struct {
+	Name   string
+	Status string
+	Code   int
+	// Message is the start of the body of the response, which describes
+	// the error.
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %s: %s", e.Name, e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Name, e.Status)
+}
+
+// Temporary reports whether the request may succeed when retried.
+func (e *StatusError) Temporary() bool {
+	return e.Code >= 500 || e.Code == http.StatusTooManyRequests || e.Code == http.StatusRequestTimeout
+}
+
+// send sends req with client, or http.DefaultClient when nil, and returns
+// the response when successful. Other responses are closed and returned as
+// a *StatusError about the file name, or an error wrapping fs.ErrNotExist
+// for 404s.
+func send(client *http.Client, req *http.Request, name string) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, &StatusError{Name: name, Status: resp.Status, Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+}
+
+// readRange sends a GET request of url for the length bytes of the file
+// name at offset, signed by sign unless it is nil. Servers ignoring the
+// Range header have the bytes before offset skipped.
+func readRange(ctx context.Context, client *http.Client, name, url string, offset, length int64, sign func(req *http.Request)) (io.ReadCloser, error) {
+	if length == 0 {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 || length > 0 {
+		end := ""
+		if length > 0 {
+			end = strconv.FormatInt(offset+length-1, 10)
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%s", offset, end))
+	}
+	if sign != nil {
+		sign(req)
+	}
+	resp, err := send(client, req, name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent && offset > 0 {
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			resp.Body.Close()
+			if err == io.EOF {
+				return nil, fmt.Errorf("%s: offset %d is past the end of the file", name, offset)
+			}
+			return nil, err
+		}
+	}
+	if length < 0 {
+		return resp.Body, nil
+	}
+	return limitCloser(resp.Body, length), nil
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+// Reader reads a file of a filesystem from an offset, with range reads. A
+// read failing on a dropped connection or a temporary server error is
+// retried by reading the file again from the offset reached, so that long
+// reads of large remote files survive network failures. Seeking reads the
+// file again from the new offset, which makes random access to records at
+// known offsets cheap on servers supporting range requests. The first read,
+// and the first after a seek, only fetch the bytes they ask for, since
+// skipping records seeks again right after reading a header; reads that
+// follow them stream the rest of the file.
+type Reader struct {
+	// Retries is the number of times in a row a failing read is retried.
+	Retries int
+	// Delay is the delay before the first retry, doubled for every other
+	// retry in a row.
+	Delay time.Duration
+
+	ctx      context.Context
+	fsys     FS
+	name     string
+	body     io.ReadCloser
+	offset   int64
+	failures int
+	// probe is set until the first read and after a seek, and bounded while
+	// the body is the range fetched by the read that follows.
+	probe, bounded bool
+}
+
+// NewReader returns a Reader of the file name of fsys, which is opened by
+// the first read. Reads stop once ctx is done.
+func NewReader(ctx context.Context, fsys FS, name string) *Reader {
+	return &Reader{Retries: 5, Delay: 100 * time.Millisecond, ctx: ctx, fsys: fsys, name: name, probe: true}
+}
+
+// open reads length bytes of the file from the offset, or all the rest when
+// length is negative, retrying temporary errors.
+func (r *Reader) open(length int64) error {
+	for {
+		body, err := r.fsys.ReadRange(r.ctx, r.name, r.offset, length)
+		if err == nil {
+			r.body, r.bounded = body, length >= 0
+			return nil
+		}
+		if !r.retry(err) {
+			return err
+		}
+	}
+}
+
+// retry waits before retrying after err, unless err is permanent or the
+// retries are exhausted.
+func (r *Reader) retry(err error) bool {
+	var statusErr *StatusError
+	switch {
+	case r.failures >= r.Retries || r.ctx.Err() != nil || errors.Is(err, fs.ErrNotExist):
+		return false
+	case errors.As(err, &statusErr) && !statusErr.Temporary():
+		return false
+	}
+	select {
+	case <-time.After(r.Delay << r.failures):
+	case <-r.ctx.Done():
+		return false
+	}
+	r.failures++
+	return true
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		if r.body == nil {
+			length := int64(-1)
+			if r.probe {
+				length = int64(len(p))
+			}
+			r.probe = false
+			if err := r.open(length); err != nil {
+				// Servers reject ranges starting at the end of the file.
+				var statusErr *StatusError
+				if errors.As(err, &statusErr) && statusErr.Code == http.StatusRequestedRangeNotSatisfiable {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+		}
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if n > 0 {
+			r.failures = 0
+		}
+		if err == io.EOF && r.bounded {
+			// The rest of the file is streamed by the next read.
+			r.body.Close()
+			r.body = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		// The file is read again from the offset reached by the next read.
+		r.body.Close()
+		r.body = nil
+		if n > 0 {
+			return n, nil
+		}
+		if !r.retry(err) {
+			return 0, err
+		}
+	}
+}
+
+// Seek sets the offset of the next read, which reads the file again from
+// there. Seeking relative to the end of the file stats it.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		info, err := r.fsys.Stat(r.ctx, r.name)
+		if err != nil {
+			return 0, err
+		}
+		offset += info.Size
+	}
+	if offset < 0 {
+		return 0, errors.New("storage: seek to a negative offset")
+	}
+	if offset != r.offset {
+		if r.body != nil {
+			r.body.Close()
+			r.body = nil
+		}
+		r.probe = true
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *Reader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/emla2805/tfr/tfrecord"
+)
+
+// flakyServer serves data, failing the requests for which fail returns a
+// non-zero status, or dropping the connection after sending drop bytes
+// when it returns a negative one. It records the Range headers received.
+type flakyServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	ranges []string
+}
+
+func newFlakyServer(data []byte, fail func(request int) int) *flakyServer {
+	s := &flakyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		request := len(s.ranges)
+		if r.Method == http.MethodGet {
+			s.ranges = append(s.ranges, r.Header.Get("Range"))
+		}
+		s.mu.Unlock()
+		switch code := fail(request); {
+		case code > 0:
+			http.Error(w, http.StatusText(code), code)
+		case code < 0 && r.Method == http.MethodGet:
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data[:-code])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		default:
+			http.ServeContent(w, r, "shard", time.Time{}, bytes.NewReader(data))
+		}
+	}))
+	return s
+}
+
+func TestReader(t *testing.T) {
+	var buf bytes.Buffer
+	for _, record := range []string{"first", "second", "third"} {
+		tfrecord.Write(&buf, []byte(record))
+	}
+	data := buf.Bytes()
+	ctx := context.Background()
+
+	// A temporary error and a dropped connection are retried, from the
+	// offset reached.
+	server := newFlakyServer(data, func(request int) int {
+		return []int{http.StatusServiceUnavailable, -30, 0}[min(request, 2)]
+	})
+	defer server.Close()
+	name := server.URL + "/shard"
+	r := NewReader(ctx, &HTTP{}, name)
+	r.Delay = time.Millisecond
+	got, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("got %q, %v, want %q", got, err, data)
+	}
+	if len(server.ranges) != 3 || server.ranges[2] != "bytes=30-" {
+		t.Errorf("got ranges %q, want the last one resuming at byte 30", server.ranges)
+	}
+
+	// Records are read from their offset after a seek. The second one
+	// follows 16 bytes of framing and the first payload.
+	second := int64(16 + len("first"))
+	if _, err := r.Seek(second, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	reader := tfrecord.NewReader(r, tfrecord.Options{Offset: second})
+	if record, err := reader.Next(); err != nil || string(record) != "second" || reader.Offset() != second {
+		t.Errorf("got %q, %v at offset %d, want the second record", record, err, reader.Offset())
+	}
+	if !strings.HasPrefix(server.ranges[3], "bytes="+strconv.FormatInt(second, 10)+"-") {
+		t.Errorf("got range %q after seeking to %d", server.ranges[3], second)
+	}
+	if offset, err := r.Seek(-4, io.SeekEnd); err != nil || offset != int64(len(data)-4) {
+		t.Errorf("got offset %d, %v seeking from the end", offset, err)
+	}
+	if _, err := r.Seek(0, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("got %d, %v reading at the end, want io.EOF", n, err)
+	}
+	r.Close()
+
+	// Missing files and other client errors are not retried, and retries
+	// stop after Retries attempts in a row.
+	var tests = []struct {
+		desc     string
+		code     int
+		requests int
+	}{
+		{"missing", http.StatusNotFound, 1},
+		{"forbidden", http.StatusForbidden, 1},
+		{"unavailable", http.StatusServiceUnavailable, 3},
+	}
+	for _, tt := range tests {
+		server := newFlakyServer(data, func(int) int { return tt.code })
+		r := NewReader(ctx, &HTTP{}, server.URL+"/shard")
+		r.Retries, r.Delay = 2, time.Millisecond
+		_, err := r.Read(make([]byte, 10))
+		var statusErr *StatusError
+		if errors.Is(err, fs.ErrNotExist) != (tt.code == http.StatusNotFound) || tt.code != http.StatusNotFound && (!errors.As(err, &statusErr) || statusErr.Code != tt.code) {
+			t.Errorf("%s: got %v", tt.desc, err)
+		}
+		if len(server.ranges) != tt.requests {
+			t.Errorf("%s: got %d requests, want %d", tt.desc, len(server.ranges), tt.requests)
+		}
+		server.Close()
+	}
+}
+
+// countingWriter counts the bytes of the responses written through it.
+type countingWriter struct {
+	http.ResponseWriter
+	n *atomic.Int64
+}
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n.Add(int64(n))
+	return n, err
+}
+
+func TestReaderSkip(t *testing.T) {
+	var buf bytes.Buffer
+	payload := bytes.Repeat([]byte("x"), 1<<20)
+	for _, last := range []byte("abc") {
+		tfrecord.Write(&buf, append(payload[:len(payload)-1:len(payload)-1], last))
+	}
+	data := buf.Bytes()
+	var served atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(countingWriter{w, &served}, r, "shard", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	r := NewReader(context.Background(), &HTTP{}, server.URL+"/shard")
+	defer r.Close()
+	reader := tfrecord.NewReader(r, tfrecord.Options{})
+	if n, err := reader.Skip(2); n != 2 || err != nil {
+		t.Fatalf("skipped %d records, %v", n, err)
+	}
+	record, err := reader.Next()
+	if err != nil || !bytes.Equal(record[:10], payload[:10]) || record[len(record)-1] != 'c' || reader.Index() != 2 {
+		t.Fatalf("got a record of %d bytes, %v at index %d, want the third", len(record), err, reader.Index())
+	}
+	// Only the headers of the skipped records, with the first bytes of
+	// their payloads in the reads fetching them, were downloaded.
+	if third := int64(len(data) / 3); served.Load() > third+3*4096 {
+		t.Errorf("served %d bytes to read a record of %d", served.Load(), third)
+	}
+	if n, err := reader.Skip(1); n != 0 || err != io.EOF {
+		t.Errorf("skipped %d records, %v past the end, want io.EOF", n, err)
+	}
+}
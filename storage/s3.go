@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 reads objects from Amazon S3 or compatible stores such as MinIO,
+// naming them s3://bucket/key. Requests are signed with AWS Signature
+// Version 4 when credentials are set.
+type S3 struct {
+	// Endpoint is the URL of a store other than AWS, addressed with the
+	// bucket in the path. When empty, buckets are addressed as hosts of
+	// s3.<region>.amazonaws.com.
+	Endpoint string
+	Region   string
+	// AccessKeyID, SecretAccessKey and SessionToken are the credentials,
+	// which public buckets do not need.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Client          *http.Client
+
+	// now returns the time requests are signed at.
+	now func() time.Time
+}
+
+// NewS3 returns an S3 configured with the variables of the AWS CLI:
+// $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY, $AWS_SESSION_TOKEN,
+// $AWS_REGION or $AWS_DEFAULT_REGION, us-east-1 by default, and
+// $AWS_ENDPOINT_URL_S3 or $AWS_ENDPOINT_URL.
+func NewS3() *S3 {
+	s := &S3{
+		Endpoint:        os.Getenv("AWS_ENDPOINT_URL_S3"),
+		Region:          os.Getenv("AWS_REGION"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if s.Endpoint == "" {
+		s.Endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if s.Region == "" {
+		s.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if s.Region == "" {
+		s.Region = "us-east-1"
+	}
+	return s
+}
+
+// bucketURL returns the URL of the bucket, ending with a slash.
+func (s *S3) bucketURL(bucket string) string {
+	if s.Endpoint == "" {
+		return "https://" + bucket + ".s3." + s.Region + ".amazonaws.com/"
+	}
+	return strings.TrimSuffix(s.Endpoint, "/") + "/" + s3Escape(bucket, false) + "/"
+}
+
+// objectURL returns the URL of the object name.
+func (s *S3) objectURL(name string) (string, error) {
+	bucket, key, err := splitURL(name, "s3")
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return "", fmt.Errorf("%s: missing key", name)
+	}
+	return s.bucketURL(bucket) + s3Escape(key, true), nil
+}
+
+func (s *S3) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return s.ReadRange(ctx, name, 0, -1)
+}
+
+func (s *S3) ReadRange(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error) {
+	u, err := s.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+	return readRange(ctx, s.Client, name, u, offset, length, s.sign)
+}
+
+func (s *S3) Stat(ctx context.Context, name string) (FileInfo, error) {
+	u, err := s.objectURL(name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	s.sign(req)
+	resp, err := send(s.Client, req, name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	resp.Body.Close()
+	return FileInfo{Name: name, Size: resp.ContentLength}, nil
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	bucket, keyPrefix, err := splitURL(prefix, "s3")
+	if err != nil {
+		return nil, err
+	}
+	var files []FileInfo
+	query := url.Values{"list-type": {"2"}, "prefix": {keyPrefix}}
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.bucketURL(bucket)+"?"+s3Query(query), nil)
+		if err != nil {
+			return nil, err
+		}
+		s.sign(req)
+		resp, err := send(s.Client, req, prefix)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key  string
+				Size int64
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", prefix, err)
+		}
+		for _, object := range page.Contents {
+			files = append(files, FileInfo{Name: "s3://" + bucket + "/" + object.Key, Size: object.Size})
+		}
+		if !page.IsTruncated {
+			return files, nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+// emptySHA256 is the hash of the empty payload of GET and HEAD requests.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign signs req with AWS Signature Version 4, unless no credentials are
+// set. Every header set on req is signed.
+func (s *S3) sign(req *http.Request) {
+	if s.AccessKeyID == "" {
+		return
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", t.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers := []string{"host"}
+	values := map[string]string{"host": req.URL.Host}
+	for name, v := range req.Header {
+		name = strings.ToLower(name)
+		headers = append(headers, name)
+		values[name] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	sort.Strings(headers)
+	var canonical strings.Builder
+	fmt.Fprintf(&canonical, "%s\n%s\n%s\n", req.Method, req.URL.EscapedPath(), s3Query(req.URL.Query()))
+	for _, name := range headers {
+		fmt.Fprintf(&canonical, "%s:%s\n", name, values[name])
+	}
+	signed := strings.Join(headers, ";")
+	fmt.Fprintf(&canonical, "\n%s\n%s", signed, emptySHA256)
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical.String()))
+	toSign := "AWS4-HMAC-SHA256\n" + t.Format("20060102T150405Z") + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	for _, part := range []string{s.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signed, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Query encodes query sorted by key as signatures need it, escaping
+// spaces as %20 rather than +.
+func s3Query(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, s3Escape(k, false)+"="+s3Escape(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape percent-encodes every byte of s but the unreserved characters
+// of RFC 3986, and slashes when keepSlash is set.
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
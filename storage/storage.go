@@ -0,0 +1,186 @@
+// Package storage reads files from the local filesystem, Google Cloud
+// Storage, Amazon S3 and HTTP servers, selected by the scheme of their
+// name: gs://bucket/object, s3://bucket/key, http:// and https:// URLs, or
+// local paths.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileInfo describes a file.
+type FileInfo struct {
+	// Name is the full name of the file, URL scheme included.
+	Name string
+	Size int64
+}
+
+// FS is a filesystem holding files named by URLs of its scheme. Errors for
+// missing files wrap fs.ErrNotExist.
+type FS interface {
+	// Open opens the file for reading.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// ReadRange opens the length bytes of the file starting at offset, or
+	// all of them to the end of the file when length is negative.
+	ReadRange(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error)
+	// Stat describes the file.
+	Stat(ctx context.Context, name string) (FileInfo, error)
+	// List describes the files whose name starts with prefix, in order of
+	// their names.
+	List(ctx context.Context, prefix string) ([]FileInfo, error)
+}
+
+// Lookup returns the filesystem holding the file name, configured from the
+// environment as NewGCS and NewS3 describe.
+func Lookup(name string) (FS, error) {
+	scheme, _, ok := strings.Cut(name, "://")
+	if !ok {
+		return Local{}, nil
+	}
+	switch scheme {
+	case "gs":
+		return NewGCS(), nil
+	case "s3":
+		return NewS3(), nil
+	case "http", "https":
+		return &HTTP{}, nil
+	}
+	return nil, fmt.Errorf("%s: unsupported scheme %q", name, scheme)
+}
+
+// IsRemote reports whether name is a URL rather than a local path.
+func IsRemote(name string) bool {
+	return strings.Contains(name, "://")
+}
+
+// Open opens the file name on its filesystem. Remote files are read through
+// a Reader, which retries failed reads, streaming the file from the start.
+func Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	fsys, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !IsRemote(name) {
+		return fsys.Open(ctx, name)
+	}
+	r := NewReader(ctx, fsys, name)
+	r.probe = false
+	if err := r.open(-1); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// HasMeta reports whether name holds any of the special characters of
+// Glob patterns.
+func HasMeta(name string) bool {
+	return strings.ContainsAny(name, `*?[\`)
+}
+
+// Glob returns the names of the files matching pattern, in order, with the
+// syntax of path.Match: a * does not match across a /. Remote files are
+// listed under the part of pattern before its first special character.
+func Glob(ctx context.Context, pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%s: %v", pattern, err)
+	}
+	if !IsRemote(pattern) {
+		return filepath.Glob(pattern)
+	}
+	fsys, err := Lookup(pattern)
+	if err != nil {
+		return nil, err
+	}
+	prefix := pattern
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		prefix = pattern[:i]
+	}
+	files, err := fsys.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if ok, _ := path.Match(pattern, f.Name); ok {
+			names = append(names, f.Name)
+		}
+	}
+	return names, nil
+}
+
+// Local is the local filesystem, naming files by their path.
+type Local struct{}
+
+func (Local) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func (Local) ReadRange(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if length < 0 {
+		return f, nil
+	}
+	return limitCloser(f, length), nil
+}
+
+func (Local) Stat(ctx context.Context, name string) (FileInfo, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return FileInfo{Name: name, Size: info.Size()}, nil
+}
+
+func (Local) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	dir := filepath.Dir(prefix)
+	if strings.HasSuffix(prefix, string(filepath.Separator)) {
+		dir = prefix
+	}
+	var files []FileInfo
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Only walk the directories that can hold names with prefix.
+			if name != dir && !strings.HasPrefix(name, prefix) && !strings.HasPrefix(prefix, name+string(filepath.Separator)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, FileInfo{Name: name, Size: info.Size()})
+		return nil
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, err
+}
+
+// limitCloser returns a ReadCloser reading up to n bytes of r.
+func limitCloser(r io.ReadCloser, n int64) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(r, n), r}
+}
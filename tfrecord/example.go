@@ -0,0 +1,114 @@
+package tfrecord
+
+import (
+	"fmt"
+	"io"
+	"iter"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// ExampleReader reads records of tf.train.Example.
+type ExampleReader struct {
+	r *Reader
+}
+
+// NewExampleReader returns an ExampleReader reading records from r.
+func NewExampleReader(r io.Reader, opts Options) *ExampleReader {
+	return &ExampleReader{r: NewReader(r, opts)}
+}
+
+// Next decodes the next record. It returns io.EOF after the last one.
+func (e *ExampleReader) Next() (*protobuf.Example, error) {
+	example := &protobuf.Example{}
+	if err := readMessage(e.r, example); err != nil {
+		return nil, err
+	}
+	return example, nil
+}
+
+// Reader returns the underlying Reader, which reports the offset of the
+// last record read.
+func (e *ExampleReader) Reader() *Reader {
+	return e.r
+}
+
+// All returns an iterator over the remaining Examples, which stops after
+// the first error.
+func (e *ExampleReader) All() iter.Seq2[*protobuf.Example, error] {
+	return allMessages(e.Next)
+}
+
+// SequenceExampleReader reads records of tf.train.SequenceExample.
+type SequenceExampleReader struct {
+	r *Reader
+}
+
+// NewSequenceExampleReader returns a SequenceExampleReader reading records
+// from r.
+func NewSequenceExampleReader(r io.Reader, opts Options) *SequenceExampleReader {
+	return &SequenceExampleReader{r: NewReader(r, opts)}
+}
+
+// Next decodes the next record. It returns io.EOF after the last one.
+func (s *SequenceExampleReader) Next() (*protobuf.SequenceExample, error) {
+	example := &protobuf.SequenceExample{}
+	if err := readMessage(s.r, example); err != nil {
+		return nil, err
+	}
+	return example, nil
+}
+
+// Reader returns the underlying Reader, which reports the offset of the
+// last record read.
+func (s *SequenceExampleReader) Reader() *Reader {
+	return s.r
+}
+
+// All returns an iterator over the remaining SequenceExamples, which stops
+// after the first error.
+func (s *SequenceExampleReader) All() iter.Seq2[*protobuf.SequenceExample, error] {
+	return allMessages(s.Next)
+}
+
+// readMessage decodes the next record of r into m.
+func readMessage(r *Reader, m proto.Message) error {
+	data, err := r.Next()
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(data, m); err != nil {
+		return &DecodeError{Offset: r.Offset(), Err: err}
+	}
+	return nil
+}
+
+// DecodeError reports a record that could not be decoded.
+type DecodeError struct {
+	// Offset is the byte offset of the record header in the stream.
+	Offset int64
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("record at offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func allMessages[M any](next func() (M, error)) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		for {
+			m, err := next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(m, err) || err != nil {
+				return
+			}
+		}
+	}
+}
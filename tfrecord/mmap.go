@@ -0,0 +1,76 @@
+package tfrecord
+
+import (
+	"fmt"
+	"os"
+)
+
+// MappedFile is a TFRecord file mapped into memory. Its records are read
+// without copying, and it can be split into ranges read concurrently, so
+// that a single large file is read on all cores.
+type MappedFile struct {
+	data []byte
+}
+
+// Mmap maps the file at path into memory for reading. Where memory
+// mapping is not supported, the file is read into memory instead.
+func Mmap(path string) (*MappedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("%s: %d bytes is too large to map", path, size)
+	}
+	data, err := mmap(f, int(size))
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return &MappedFile{data: data}, nil
+}
+
+// Bytes returns the contents of the file, which are only valid until the
+// file is closed.
+func (f *MappedFile) Bytes() []byte {
+	return f.data
+}
+
+// Size returns the size of the file in bytes.
+func (f *MappedFile) Size() int64 {
+	return int64(len(f.data))
+}
+
+// Reader returns a reader of all the records of the file.
+func (f *MappedFile) Reader(opts Options) *SliceReader {
+	opts.Offset = 0
+	return NewSliceReader(f.data, opts)
+}
+
+// Split divides the file into up to n ranges, as Split does.
+func (f *MappedFile) Split(n int) []Range {
+	return Split(f.data, n)
+}
+
+// RangeReader returns a reader of the records starting within r. It reads
+// the last of them past the end of r, and fails once done when that one
+// does not end exactly at the end of r, which reveals a range boundary
+// found within a payload.
+func (f *MappedFile) RangeReader(r Range, opts Options) *SliceReader {
+	opts.Offset = r.Start
+	reader := NewSliceReader(f.data[r.Start:], opts)
+	reader.end = int(r.End - r.Start)
+	return reader
+}
+
+// Close unmaps the file. The payloads read from it must no longer be used.
+func (f *MappedFile) Close() error {
+	data := f.data
+	f.data = nil
+	return munmap(data)
+}
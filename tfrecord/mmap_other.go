@@ -0,0 +1,21 @@
+//go:build !unix
+
+package tfrecord
+
+import (
+	"io"
+	"os"
+)
+
+// mmap reads the file into memory where memory mapping is not supported.
+func mmap(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package tfrecord
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+)
+
+func TestSliceReader(t *testing.T) {
+	first, second := encodeRecord([]byte("first")), encodeRecord([]byte("second record"))
+	corrupt := append([]byte{}, second...)
+	corrupt[len(corrupt)-1] ^= 0xff
+	stream := append(append([]byte{}, first...), corrupt...)
+
+	reader := NewSliceReader(stream, Options{Offset: 100, IgnoreChecksums: true})
+	data, err := reader.Next()
+	if err != nil || string(data) != "first" || reader.Offset() != 100 || !reader.ChecksumOK() {
+		t.Errorf("got %q, %v at offset %d", data, err, reader.Offset())
+	}
+	// Payloads alias the slice.
+	if unsafe.SliceData(data) != &stream[headerLen] || cap(data) != len(data) {
+		t.Errorf("got a payload not aliasing the slice")
+	}
+	data, err = reader.Next()
+	if err != nil || string(data) != "second record" || reader.Index() != 1 || reader.ChecksumOK() {
+		t.Errorf("got %q, %v at index %d", data, err, reader.Index())
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("got %v at the end, want io.EOF", err)
+	}
+
+	if _, err := NewSliceReader(corrupt, Options{}).Next(); err == nil || err.Error() != "invalid crc for payload at offset 0" {
+		t.Errorf("got %v for a corrupt payload", err)
+	}
+	for _, truncated := range [][]byte{first[:len(first)-2], first[:headerLen-1], first[:headerLen+2]} {
+		if _, err := NewSliceReader(truncated, Options{}).Next(); err != io.ErrUnexpectedEOF {
+			t.Errorf("got %v for %d bytes, want io.ErrUnexpectedEOF", err, len(truncated))
+		}
+	}
+	badLength := append([]byte{}, first...)
+	badLength[0] ^= 0xff
+	if _, err := NewSliceReader(badLength, Options{}).Next(); err == nil || err.Error() != "invalid crc for length at offset 0" {
+		t.Errorf("got %v for a corrupt length", err)
+	}
+}
+
+// writeRecords writes records of random sizes to a file, some of them
+// holding framed records themselves, and returns its path and payloads.
+func writeRecords(t *testing.T, n int) (string, [][]byte) {
+	rng := rand.New(rand.NewSource(1))
+	var buf bytes.Buffer
+	payloads := make([][]byte, n)
+	for i := range payloads {
+		payloads[i] = make([]byte, rng.Intn(3000))
+		rng.Read(payloads[i])
+		if i%7 == 0 {
+			payloads[i] = encodeRecord(payloads[i])
+		}
+		Write(&buf, payloads[i])
+	}
+	path := filepath.Join(t.TempDir(), "data.tfrecord")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, payloads
+}
+
+func TestMappedFileSplit(t *testing.T) {
+	path, payloads := writeRecords(t, 500)
+	f, err := Mmap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records int
+	for data, err := range f.Reader(Options{}).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if records < len(payloads) && !bytes.Equal(data, payloads[records]) {
+			t.Fatalf("record %d: got a different payload", records)
+		}
+		records++
+	}
+	if records != len(payloads) {
+		t.Fatalf("got %d records, want %d", records, len(payloads))
+	}
+
+	for _, n := range []int{1, 2, 3, 8, 64, 5000} {
+		ranges := f.Split(n)
+		if len(ranges) > n || ranges[0].Start != 0 || ranges[len(ranges)-1].End != f.Size() {
+			t.Errorf("%d ranges: got %v", n, ranges)
+		}
+		var got int
+		for i, r := range ranges {
+			if i > 0 && r.Start != ranges[i-1].End {
+				t.Errorf("%d ranges: range %d starts at %d, want %d", n, i, r.Start, ranges[i-1].End)
+			}
+			reader := f.RangeReader(r, Options{})
+			for data, err := range reader.All() {
+				if err != nil {
+					t.Fatalf("%d ranges: range %d: %v", n, i, err)
+				}
+				if got >= len(payloads) || !bytes.Equal(data, payloads[got]) {
+					t.Fatalf("%d ranges: record %d: got a different payload", n, got)
+				}
+				got++
+			}
+		}
+		if got != len(payloads) {
+			t.Errorf("%d ranges: got %d records, want %d", n, got, len(payloads))
+		}
+	}
+
+	// A range ending within a record fails once its last record is read.
+	data := f.Bytes()
+	second := Resync(data, 1)
+	reader := f.RangeReader(Range{Start: 0, End: second - 1}, Options{})
+	if _, err := reader.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.Next(); err == nil || err == io.EOF {
+		t.Errorf("got %v for a range ending within a record", err)
+	}
+	if end := Resync(data, f.Size()-2); end != f.Size() {
+		t.Errorf("got boundary %d past the last record, want %d", end, f.Size())
+	}
+}
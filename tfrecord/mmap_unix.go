@@ -0,0 +1,24 @@
+//go:build unix
+
+package tfrecord
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func mmap(f *os.File, size int) ([]byte, error) {
+	// Empty files cannot be mapped.
+	if size == 0 {
+		return nil, nil
+	}
+	return unix.Mmap(int(f.Fd()), 0, size, unix.PROT_READ, unix.MAP_SHARED)
+}
+
+func munmap(data []byte) error {
+	if data == nil {
+		return nil
+	}
+	return unix.Munmap(data)
+}
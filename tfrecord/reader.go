@@ -0,0 +1,249 @@
+// Package tfrecord reads and writes TFRecord files, the format TensorFlow
+// stores tf.train.Example and other serialized protos in.
+//
+// Each record is framed as
+//
+//	uint64 length
+//	uint32 masked crc32c of length
+//	byte   data[length]
+//	uint32 masked crc32c of data
+//
+// with integers in little-endian byte order.
+package tfrecord
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+)
+
+const (
+	maskDelta = 0xa282ead8
+	headerLen = 12
+	footerLen = 4
+)
+
+// DefaultMaxRecordSize is the largest payload a Reader reads by default,
+// that of the largest serialized protocol buffer.
+const DefaultMaxRecordSize = 2<<30 - 1
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// maskChecksum returns the masked crc32c checksum TFRecords store of data.
+func maskChecksum(data []byte) uint32 {
+	crc := crc32.Checksum(data, crc32c)
+	return ((crc >> 15) | (crc << 17)) + maskDelta
+}
+
+// ChecksumError reports a record whose length or payload does not match
+// its checksum.
+type ChecksumError struct {
+	// Offset is the byte offset of the record header in the stream.
+	Offset int64
+	// Payload is set when the payload, rather than the length, is corrupt.
+	Payload bool
+}
+
+func (e *ChecksumError) Error() string {
+	if e.Payload {
+		return fmt.Sprintf("invalid crc for payload at offset %d", e.Offset)
+	}
+	return fmt.Sprintf("invalid crc for length at offset %d", e.Offset)
+}
+
+// Options configure a Reader.
+type Options struct {
+	// Context, when set, stops the reader once it is done: Next returns
+	// the error of the context. It is checked between records.
+	Context context.Context
+	// IgnoreChecksums returns records whose payload does not match its
+	// checksum rather than a *ChecksumError, since their length could be
+	// trusted. ChecksumOK reports them.
+	IgnoreChecksums bool
+	// Offset is the offset of the first record in the underlying stream,
+	// for readers starting within a file.
+	Offset int64
+	// MaxRecordSize is the largest payload read, DefaultMaxRecordSize when
+	// zero. Longer records are reported as errors rather than allocated,
+	// since their length may be corrupt despite its checksum.
+	MaxRecordSize int64
+}
+
+// Reader reads TFRecords one at a time, up to Options.MaxRecordSize bytes
+// long, and keeps track of their offsets.
+type Reader struct {
+	r      *bufio.Reader
+	src    io.Reader
+	seeker io.Seeker
+	opts   Options
+	offset int64
+	header [headerLen]byte
+	footer [footerLen]byte
+
+	// The position and checksum status of the last record returned.
+	recordOffset int64
+	index        int
+	checksumOK   bool
+}
+
+// NewReader returns a Reader reading records from r. When r is an
+// io.Seeker, Skip seeks past the payloads of the records it skips.
+func NewReader(r io.Reader, opts Options) *Reader {
+	reader := &Reader{r: bufio.NewReader(r), src: r, opts: opts, offset: opts.Offset, index: -1}
+	// Pipes are files that cannot seek.
+	if s, ok := r.(io.Seeker); ok {
+		if _, err := s.Seek(0, io.SeekCurrent); err == nil {
+			reader.seeker = s
+		}
+	}
+	return reader
+}
+
+// Next returns the payload of the next record. It returns io.EOF when the
+// stream ends cleanly between records and io.ErrUnexpectedEOF when it ends
+// within one.
+func (r *Reader) Next() ([]byte, error) {
+	if ctx := r.opts.Context; ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := io.ReadFull(r.r, r.header[:]); err != nil {
+		return nil, err
+	}
+	length, err := r.length()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if _, err := io.ReadFull(r.r, r.footer[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	checksumOK := maskChecksum(data) == binary.LittleEndian.Uint32(r.footer[:])
+	if !checksumOK && !r.opts.IgnoreChecksums {
+		return nil, &ChecksumError{Offset: r.offset, Payload: true}
+	}
+
+	r.recordOffset, r.checksumOK = r.offset, checksumOK
+	r.index++
+	r.offset += headerLen + int64(length) + footerLen
+	return data, nil
+}
+
+// Skip skips the next n records, returning the number skipped, which is
+// less than n only with an error, io.EOF when the stream ends first. Only
+// the headers of the records are read and checked: their payloads are
+// discarded, or seeked past when not buffered if the underlying reader is
+// an io.Seeker, so that they are not even fetched from remote files. A last
+// record truncated within a payload seeked past then goes unnoticed.
+func (r *Reader) Skip(n int) (int, error) {
+	for i := 0; i < n; i++ {
+		if ctx := r.opts.Context; ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return i, err
+			}
+		}
+		if _, err := io.ReadFull(r.r, r.header[:]); err != nil {
+			return i, err
+		}
+		length, err := r.length()
+		if err != nil {
+			return i, err
+		}
+		if err := r.discard(length + footerLen); err != nil {
+			return i, unexpectedEOF(err)
+		}
+		r.index++
+		r.offset += headerLen + int64(length) + footerLen
+	}
+	return n, nil
+}
+
+// length returns the payload length of the record whose header was read,
+// checked against its checksum and the maximum record size.
+func (r *Reader) length() (int64, error) {
+	length, ok := recordLength(r.header[:])
+	if !ok {
+		return 0, &ChecksumError{Offset: r.offset}
+	}
+	max := r.opts.MaxRecordSize
+	if max <= 0 {
+		max = DefaultMaxRecordSize
+	}
+	if length > uint64(max) {
+		return 0, fmt.Errorf("record length %d at offset %d exceeds the maximum of %d", length, r.offset, max)
+	}
+	return int64(length), nil
+}
+
+// discard skips the next n bytes of the stream.
+func (r *Reader) discard(n int64) error {
+	buffered := int64(r.r.Buffered())
+	if n <= buffered || r.seeker == nil {
+		_, err := io.CopyN(io.Discard, r.r, n)
+		return err
+	}
+	if _, err := r.seeker.Seek(n-buffered, io.SeekCurrent); err != nil {
+		return err
+	}
+	r.r.Reset(r.src)
+	return nil
+}
+
+// recordLength returns the length stored in a record header and whether it
+// matches its checksum.
+func recordLength(header []byte) (uint64, bool) {
+	length := binary.LittleEndian.Uint64(header[0:8])
+	return length, maskChecksum(header[0:8]) == binary.LittleEndian.Uint32(header[8:12])
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Offset returns the byte offset of the header of the last record returned
+// by Next in the stream.
+func (r *Reader) Offset() int64 {
+	return r.recordOffset
+}
+
+// Index returns the index of the last record returned by Next, counting
+// from 0.
+func (r *Reader) Index() int {
+	return r.index
+}
+
+// ChecksumOK reports whether the payload of the last record returned by
+// Next matched its checksum, which is only not the case with
+// IgnoreChecksums.
+func (r *Reader) ChecksumOK() bool {
+	return r.checksumOK
+}
+
+// All returns an iterator over the payloads of the remaining records. It
+// stops after the first error, which it yields, and at the end of the
+// stream.
+func (r *Reader) All() iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		for {
+			data, err := r.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(data, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package tfrecord
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// encodeRecord frames data as a TFRecord.
+func encodeRecord(data []byte) []byte {
+	var buf bytes.Buffer
+	Write(&buf, data)
+	return buf.Bytes()
+}
+
+func TestReader(t *testing.T) {
+	first, second := encodeRecord([]byte("first")), encodeRecord([]byte("second record"))
+	corrupt := append([]byte{}, second...)
+	corrupt[len(corrupt)-1] ^= 0xff
+	stream := append(append([]byte{}, first...), corrupt...)
+
+	var tests = []struct {
+		desc       string
+		opts       Options
+		data       []string
+		offsets    []int64
+		checksumOK []bool
+		err        error
+	}{
+		{"corrupt payload", Options{}, []string{"first"}, []int64{0}, []bool{true},
+			&ChecksumError{Offset: int64(len(first)), Payload: true}},
+		{"ignored checksums", Options{IgnoreChecksums: true}, []string{"first", "second record"},
+			[]int64{0, int64(len(first))}, []bool{true, false}, io.EOF},
+		{"starting offset", Options{Offset: 100, IgnoreChecksums: true}, []string{"first", "second record"},
+			[]int64{100, 100 + int64(len(first))}, []bool{true, false}, io.EOF},
+	}
+	for _, tt := range tests {
+		reader := NewReader(bytes.NewReader(stream), tt.opts)
+		for i, want := range tt.data {
+			data, err := reader.Next()
+			if err != nil {
+				t.Fatalf("%s: record %d: unexpected error: %v", tt.desc, i, err)
+			}
+			if string(data) != want || reader.Index() != i || reader.Offset() != tt.offsets[i] || reader.ChecksumOK() != tt.checksumOK[i] {
+				t.Errorf("%s: record %d: got %q at index %d offset %d checksum ok %v", tt.desc, i,
+					data, reader.Index(), reader.Offset(), reader.ChecksumOK())
+			}
+		}
+		_, err := reader.Next()
+		var checksumErr *ChecksumError
+		if errors.As(tt.err, &checksumErr) {
+			if got, ok := err.(*ChecksumError); !ok || *got != *checksumErr {
+				t.Errorf("%s: got %v, want %v", tt.desc, err, tt.err)
+			}
+		} else if err != tt.err {
+			t.Errorf("%s: got %v, want %v", tt.desc, err, tt.err)
+		}
+	}
+
+	truncated := NewReader(bytes.NewReader(first[:len(first)-2]), Options{})
+	if _, err := truncated.Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("got %v for a truncated record, want io.ErrUnexpectedEOF", err)
+	}
+	badLength := append([]byte{}, first...)
+	badLength[0] ^= 0xff
+	if _, err := NewReader(bytes.NewReader(badLength), Options{}).Next(); err == nil || err.Error() != "invalid crc for length at offset 0" {
+		t.Errorf("got %v for a corrupt length", err)
+	}
+
+	// Lengths beyond the maximum are not allocated, even with a valid
+	// checksum.
+	tooLarge := NewReader(bytes.NewReader(stream), Options{MaxRecordSize: 5})
+	if data, err := tooLarge.Next(); err != nil || string(data) != "first" {
+		t.Fatalf("got %q, %v for a record of the maximum size", data, err)
+	}
+	want := fmt.Sprintf("record length 13 at offset %d exceeds the maximum of 5", len(first))
+	if _, err := tooLarge.Next(); err == nil || err.Error() != want {
+		t.Errorf("got %v, want %s", err, want)
+	}
+	huge := make([]byte, headerLen, headerLen+footerLen)
+	binary.LittleEndian.PutUint64(huge, 1<<62)
+	binary.LittleEndian.PutUint32(huge[8:], maskChecksum(huge[:8]))
+	huge = append(huge, 0, 0, 0, 0)
+	if _, err := NewReader(bytes.NewReader(huge), Options{}).Next(); err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Errorf("got %v for a huge length", err)
+	}
+	if _, err := NewReader(bytes.NewReader(huge), Options{}).Skip(1); err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Errorf("got %v skipping a huge length", err)
+	}
+}
+
+func TestReaderContext(t *testing.T) {
+	stream := append(encodeRecord([]byte("a")), encodeRecord([]byte("b"))...)
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := NewReader(bytes.NewReader(stream), Options{Context: ctx})
+	if _, err := reader.Next(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := reader.Next(); err != context.Canceled {
+		t.Errorf("got %v after cancel, want context.Canceled", err)
+	}
+}
+
+func TestReaderAll(t *testing.T) {
+	stream := append(append(encodeRecord([]byte("a")), encodeRecord([]byte("b"))...), encodeRecord([]byte("c"))...)
+
+	var got []string
+	for data, err := range NewReader(bytes.NewReader(stream), Options{}).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(data))
+	}
+	if len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Errorf("got %q", got)
+	}
+
+	// Breaking out of the loop leaves the remaining records to Next.
+	reader := NewReader(bytes.NewReader(stream), Options{})
+	for range reader.All() {
+		break
+	}
+	if data, err := reader.Next(); err != nil || string(data) != "b" {
+		t.Errorf("got %q, %v after break, want b", data, err)
+	}
+
+	// Errors are yielded once and end the iteration.
+	var errs int
+	for _, err := range NewReader(bytes.NewReader(stream[:len(stream)-1]), Options{}).All() {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors, want 1", errs)
+	}
+}
+
+func TestReaderSkip(t *testing.T) {
+	// Payloads larger than the buffer of the Reader are seeked past.
+	large := bytes.Repeat([]byte("x"), 10000)
+	first, second := encodeRecord(large), encodeRecord([]byte("second"))
+	stream := append(append(append([]byte{}, first...), second...), first...)
+
+	for _, r := range []io.Reader{bytes.NewReader(stream), struct{ io.Reader }{bytes.NewReader(stream)}} {
+		reader := NewReader(r, Options{})
+		if n, err := reader.Skip(1); n != 1 || err != nil {
+			t.Fatalf("%T: skipped %d records, %v", r, n, err)
+		}
+		data, err := reader.Next()
+		if err != nil || string(data) != "second" || reader.Index() != 1 || reader.Offset() != int64(len(first)) {
+			t.Errorf("%T: got %q, %v at index %d offset %d, want the second record", r, data, err, reader.Index(), reader.Offset())
+		}
+		if n, err := reader.Skip(2); n != 1 || err != io.EOF {
+			t.Errorf("%T: skipped %d records, %v past the end, want 1, io.EOF", r, n, err)
+		}
+	}
+
+	badLength := append([]byte{}, first...)
+	badLength[0] ^= 0xff
+	if _, err := NewReader(bytes.NewReader(badLength), Options{}).Skip(1); err == nil {
+		t.Errorf("got no error skipping a record with a corrupt length")
+	}
+}
+
+func TestExampleReader(t *testing.T) {
+	var buf bytes.Buffer
+	for _, age := range []int64{29, 31} {
+		data, err := proto.Marshal(protobuf.NewExample().Int64("age", age).Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		Write(&buf, data)
+	}
+	Write(&buf, []byte{0xff})
+
+	reader := NewExampleReader(&buf, Options{})
+	var ages []int64
+	var err error
+	for example, e := range reader.All() {
+		if e != nil {
+			err = e
+			break
+		}
+		age, err := example.Int64s("age")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ages = append(ages, age...)
+	}
+	if len(ages) != 2 || ages[0] != 29 || ages[1] != 31 {
+		t.Errorf("got ages %v", ages)
+	}
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Offset != reader.Reader().Offset() {
+		t.Errorf("got %v, want a DecodeError for the last record", err)
+	}
+}
@@ -0,0 +1,156 @@
+package tfrecord
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// SliceReader reads TFRecords held in memory, such as those of a
+// MappedFile. The payloads it returns alias the slice rather than being
+// copied, so they must not be modified and are only valid as long as the
+// slice is.
+type SliceReader struct {
+	data []byte
+	opts Options
+	pos  int
+	// end is the position records start before, which may be before the
+	// end of data for readers of a Range.
+	end int
+
+	// The position and checksum status of the last record returned.
+	recordOffset int64
+	index        int
+	checksumOK   bool
+}
+
+// NewSliceReader returns a SliceReader reading the records of data. The
+// Offset of opts is the offset of data in its file.
+func NewSliceReader(data []byte, opts Options) *SliceReader {
+	return &SliceReader{data: data, opts: opts, end: len(data), index: -1}
+}
+
+// Next returns the payload of the next record. It returns io.EOF at the end
+// of the slice and io.ErrUnexpectedEOF when the slice ends within a record.
+func (r *SliceReader) Next() ([]byte, error) {
+	if ctx := r.opts.Context; ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if r.pos > r.end {
+		return nil, fmt.Errorf("offset %d is not a record boundary, the record at offset %d ends at %d",
+			r.opts.Offset+int64(r.end), r.recordOffset, r.opts.Offset+int64(r.pos))
+	}
+	if r.pos == r.end {
+		return nil, io.EOF
+	}
+	offset := r.opts.Offset + int64(r.pos)
+	rest := r.data[r.pos:]
+	if len(rest) < headerLen {
+		return nil, io.ErrUnexpectedEOF
+	}
+	length, ok := recordLength(rest)
+	if !ok {
+		return nil, &ChecksumError{Offset: offset}
+	}
+	if length > uint64(len(rest)-headerLen) || uint64(len(rest)-headerLen)-length < footerLen {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	end := headerLen + int(length)
+	data := rest[headerLen:end:end]
+	checksumOK := maskChecksum(data) == binary.LittleEndian.Uint32(rest[end:])
+	if !checksumOK && !r.opts.IgnoreChecksums {
+		return nil, &ChecksumError{Offset: offset, Payload: true}
+	}
+
+	r.recordOffset, r.checksumOK = offset, checksumOK
+	r.index++
+	r.pos += end + footerLen
+	return data, nil
+}
+
+// Offset returns the byte offset of the header of the last record returned
+// by Next in the file.
+func (r *SliceReader) Offset() int64 {
+	return r.recordOffset
+}
+
+// Index returns the index of the last record returned by Next, counting
+// from 0 at the start of the slice or Range.
+func (r *SliceReader) Index() int {
+	return r.index
+}
+
+// ChecksumOK reports whether the payload of the last record returned by
+// Next matched its checksum, which is only not the case with
+// IgnoreChecksums.
+func (r *SliceReader) ChecksumOK() bool {
+	return r.checksumOK
+}
+
+// All returns an iterator over the payloads of the remaining records. It
+// stops after the first error, which it yields, and at the end of the
+// slice.
+func (r *SliceReader) All() iter.Seq2[[]byte, error] {
+	return allMessages(r.Next)
+}
+
+// Range is a byte range of a TFRecord file starting at a record boundary.
+type Range struct {
+	Start, End int64
+}
+
+// Split divides the records of data into up to n ranges of about the same
+// size, for concurrent readers. The ranges start at the boundaries found
+// by Resync from evenly spaced offsets, so a record larger than a range
+// leaves fewer ranges.
+func Split(data []byte, n int) []Range {
+	var ranges []Range
+	size := int64(len(data))
+	var start int64
+	for i := 1; i <= n && start < size; i++ {
+		end := size
+		if i < n {
+			end = Resync(data, size*int64(i)/int64(n))
+		}
+		if end > start {
+			ranges = append(ranges, Range{Start: start, End: end})
+			start = end
+		}
+	}
+	return ranges
+}
+
+// Resync returns the offset of the first record boundary of data at or
+// after offset, or len(data) when there is none. A boundary is a header
+// whose length matches its checksum and which is followed, after the
+// record, by the end of data or by another such header, so that a
+// boundary is only found within a payload when the payload itself holds
+// framed records.
+func Resync(data []byte, offset int64) int64 {
+	size := int64(len(data))
+	for p := offset; p+headerLen <= size; p++ {
+		// Most positions hold a length beyond the end of data, which is
+		// cheaper to rule out than the checksum.
+		length := binary.LittleEndian.Uint64(data[p:])
+		if length > uint64(size-p-headerLen) {
+			continue
+		}
+		next := p + headerLen + int64(length) + footerLen
+		if _, ok := recordLength(data[p:]); !ok || next > size {
+			continue
+		}
+		if next == size {
+			return p
+		}
+		if next+headerLen <= size {
+			if _, ok := recordLength(data[next:]); ok {
+				return p
+			}
+		}
+	}
+	return size
+}
@@ -0,0 +1,24 @@
+package tfrecord
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Write writes data to w framed as a single TFRecord.
+func Write(w io.Writer, data []byte) error {
+	var header [headerLen]byte
+	binary.LittleEndian.PutUint64(header[0:8], uint64(len(data)))
+	binary.LittleEndian.PutUint32(header[8:12], maskChecksum(header[0:8]))
+	var footer [footerLen]byte
+	binary.LittleEndian.PutUint32(footer[:], maskChecksum(data))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	_, err := w.Write(footer[:])
+	return err
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// LoadDescriptorSet reads a serialized FileDescriptorSet, as written by
+// protoc --descriptor_set_out with --include_imports, and returns its files
+// and a registry of dynamic types for all of their messages.
+func LoadDescriptorSet(path string) (*protoregistry.Files, *protoregistry.Types, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", path, err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	types := &protoregistry.Types{}
+	files.RangeFiles(func(fd pref.FileDescriptor) bool {
+		err = registerMessages(types, fd.Messages())
+		return err == nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return files, types, nil
+}
+
+func registerMessages(types *protoregistry.Types, messages pref.MessageDescriptors) error {
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if md.IsMapEntry() {
+			continue
+		}
+		if err := types.RegisterMessage(dynamicpb.NewMessageType(md)); err != nil {
+			return err
+		}
+		if err := registerMessages(types, md.Messages()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadMessageType returns the dynamic type of the message called name, e.g.
+// my.pkg.Event, in the descriptor set at path, and a resolver of the types
+// of the set, before the linked in types, for google.protobuf.Any messages.
+func LoadMessageType(path, name string) (pref.MessageType, TypeResolver, error) {
+	files, types, err := LoadDescriptorSet(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	d, err := files.FindDescriptorByName(pref.FullName(name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: message %s: %v", path, name, err)
+	}
+	md, ok := d.(pref.MessageDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: %s is not a message", path, name)
+	}
+	return dynamicpb.NewMessageType(md), chainResolver{types, protoregistry.GlobalTypes}, nil
+}
+
+// chainResolver resolves type URLs with the first resolver that knows them.
+type chainResolver []TypeResolver
+
+func (c chainResolver) FindMessageByURL(url string) (pref.MessageType, error) {
+	for _, r := range c {
+		if mt, err := r.FindMessageByURL(url); err == nil {
+			return mt, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
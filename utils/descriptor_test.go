@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventFile describes
+//
+//	syntax = "proto3";
+//	package test;
+//	import "google/protobuf/timestamp.proto";
+//	enum Level { DEBUG = 0; INFO = 1; }
+//	message Event {
+//	  string name = 1; bool ok = 2; Level level = 3; int32 i32 = 4;
+//	  sint64 s64 = 5; uint64 u64 = 6; fixed32 f32 = 7; double d = 8;
+//	  repeated sfixed64 ids = 9; google.protobuf.Timestamp time = 10;
+//	  Event parent = 11; oneof payload { string text = 12; bytes raw = 13; }
+//	  map<int32, string> tags = 14;
+//	}
+func eventFile() *descriptorpb.FileDescriptorProto {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	text := field("text", 12, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, "")
+	text.OneofIndex = proto.Int32(0)
+	raw := field("raw", 13, descriptorpb.FieldDescriptorProto_TYPE_BYTES, optional, "")
+	raw.OneofIndex = proto.Int32(0)
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/event.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Level"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("DEBUG"), Number: proto.Int32(0)},
+				{Name: proto.String("INFO"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Event"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				field("ok", 2, descriptorpb.FieldDescriptorProto_TYPE_BOOL, optional, ""),
+				field("level", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".test.Level"),
+				field("i32", 4, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+				field("s64", 5, descriptorpb.FieldDescriptorProto_TYPE_SINT64, optional, ""),
+				field("u64", 6, descriptorpb.FieldDescriptorProto_TYPE_UINT64, optional, ""),
+				field("f32", 7, descriptorpb.FieldDescriptorProto_TYPE_FIXED32, optional, ""),
+				field("d", 8, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, optional, ""),
+				field("ids", 9, descriptorpb.FieldDescriptorProto_TYPE_SFIXED64, repeated, ""),
+				field("time", 10, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Timestamp"),
+				field("parent", 11, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".test.Event"),
+				text,
+				raw,
+				field("tags", 14, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.Event.TagsEntry"),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("TagsEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("payload")}},
+		}},
+	}
+}
+
+func writeDescriptorSet(t *testing.T) string {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		eventFile(),
+	}}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "descriptor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "event.pb")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMessageType(t *testing.T) {
+	path := writeDescriptorSet(t)
+	for _, name := range []string{"test.Missing", "test.Level"} {
+		if _, _, err := LoadMessageType(path, name); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	mt, resolver, err := LoadMessageType(path, "test.Event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := mt.New()
+	fields := m.Descriptor().Fields()
+	set := func(name string, v pref.Value) {
+		m.Set(fields.ByName(pref.Name(name)), v)
+	}
+	set("name", pref.ValueOfString("start \"now\""))
+	set("ok", pref.ValueOfBool(true))
+	set("level", pref.ValueOfEnum(1))
+	set("i32", pref.ValueOfInt32(-3))
+	set("s64", pref.ValueOfInt64(-9007199254740993))
+	set("u64", pref.ValueOfUint64(18446744073709551615))
+	set("f32", pref.ValueOfUint32(7))
+	set("d", pref.ValueOfFloat64(0.1))
+	ids := m.Mutable(fields.ByName("ids")).List()
+	ids.Append(pref.ValueOfInt64(1))
+	ids.Append(pref.ValueOfInt64(-2))
+	set("time", pref.ValueOfMessage((&timestamppb.Timestamp{Seconds: 1}).ProtoReflect()))
+	parent := m.NewField(fields.ByName("parent")).Message()
+	parent.Set(fields.ByName("level"), pref.ValueOfEnum(7))
+	set("parent", pref.ValueOfMessage(parent))
+	set("raw", pref.ValueOfBytes([]byte("\xffpayload")))
+	tags := m.Mutable(fields.ByName("tags")).Map()
+	tags.Set(pref.ValueOfInt32(10).MapKey(), pref.ValueOfString("b"))
+	tags.Set(pref.ValueOfInt32(2).MapKey(), pref.ValueOfString("a"))
+
+	// Round trip through the wire format as records are read.
+	data, err := proto.Marshal(m.Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := mt.New().Interface()
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	json, err := Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"start \"now\"","ok":true,"level":"INFO","i32":-3,"s64":-9007199254740993,` +
+		`"u64":18446744073709551615,"f32":7,"d":0.1,"ids":[1,-2],"time":"1970-01-01T00:00:01Z",` +
+		`"parent":{"name":"","ok":false,"level":7,"i32":0,"s64":0,"u64":0,"f32":0,"d":0,"ids":[],"tags":{}},` +
+		`"raw":"/3BheWxvYWQ=","tags":{"2":"a","10":"b"}}`
+	if string(json) != want {
+		t.Errorf("\ngot:  %s\nwant: %s", json, want)
+	}
+
+	// Only the resolver of the set knows its types in Any messages.
+	parent.Set(fields.ByName("name"), pref.ValueOfString("p"))
+	value, err := proto.Marshal(parent.Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &anypb.Any{TypeUrl: "type.googleapis.com/test.Event", Value: value}
+	var anyTests = []struct {
+		opts MarshalOptions
+		want string
+	}{
+		{MarshalOptions{}, `{"@type":"type.googleapis.com/test.Event","value":"` + base64.StdEncoding.EncodeToString(value) + `"}`},
+		{MarshalOptions{Resolver: resolver}, `{"@type":"type.googleapis.com/test.Event","name":"p","ok":false,"level":7,` +
+			`"i32":0,"s64":0,"u64":0,"f32":0,"d":0,"ids":[],"tags":{}}`},
+	}
+	for _, tt := range anyTests {
+		if json, err := tt.opts.Marshal(a); err != nil || string(json) != tt.want {
+			t.Errorf("got %s, %v, want %s", json, err, tt.want)
+		}
+	}
+}
+
+// TestVendoredExample checks that Examples described under another path, as
+// in a descriptor set built from a vendored example.proto, are written the
+// same.
+func TestVendoredExample(t *testing.T) {
+	feature := protodesc.ToFileDescriptorProto(protobuf.File_tensorflow_core_example_feature_proto)
+	example := protodesc.ToFileDescriptorProto(protobuf.File_tensorflow_core_example_example_proto)
+	feature.Name = proto.String("vendor/feature.proto")
+	example.Name = proto.String("vendor/example.proto")
+	example.Dependency = []string{"vendor/feature.proto"}
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{feature, example}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := files.FindDescriptorByName("tensorflow.Example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	json, err := Marshal(dynamicpb.NewMessage(d.(pref.MessageDescriptor)))
+	if want := `{"features":{"feature":{}}}`; err != nil || string(json) != want {
+		t.Errorf("got %s, %v, want %s", json, err, want)
+	}
+}
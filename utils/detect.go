@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Record types.
+const (
+	RecordExample         = "example"
+	RecordSequenceExample = "sequence_example"
+)
+
+// DetectRecordType tells Examples from SequenceExamples by the wire
+// structure of serialized records: an Example holds Features in field 1, a
+// SequenceExample context Features in field 1 and FeatureLists in field 2.
+// A SequenceExample without feature lists is indistinguishable from an
+// Example, so records are only detected as SequenceExamples when one of
+// them has feature lists. Records that are neither are an error.
+func DetectRecordType(records [][]byte) (string, error) {
+	recordType := RecordExample
+	for i, data := range records {
+		sequence, ok := checkRecord(data)
+		if !ok {
+			return "", fmt.Errorf("record %d is neither an Example nor a SequenceExample", i)
+		}
+		if sequence {
+			recordType = RecordSequenceExample
+		}
+	}
+	return recordType, nil
+}
+
+// checkRecord reports whether data is an Example or SequenceExample, and
+// whether it has feature lists.
+func checkRecord(data []byte) (sequence, ok bool) {
+	ok = checkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) bool {
+		switch {
+		case num == exampleFeaturesField && typ == protowire.BytesType:
+			return checkFeatureMap(value, checkFeature)
+		case num == sequenceExampleFeatureListField && typ == protowire.BytesType:
+			sequence = true
+			return checkFeatureMap(value, checkFeatureList)
+		}
+		return false
+	})
+	return sequence, ok
+}
+
+// checkFields calls check with every field of a serialized message, and
+// reports whether the message is well formed and check accepted all of them.
+// Length-delimited values are passed without their length prefix.
+func checkFields(data []byte, check func(num protowire.Number, typ protowire.Type, value []byte) bool) bool {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return false
+		}
+		size := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if size < 0 {
+			return false
+		}
+		value := data[n : n+size]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		if !check(num, typ, value) {
+			return false
+		}
+		data = data[n+size:]
+	}
+	return true
+}
+
+// checkFeatureMap checks a serialized Features or FeatureLists message,
+// checking its map values with checkValue.
+func checkFeatureMap(data []byte, checkValue func([]byte) bool) bool {
+	return checkFields(data, func(num protowire.Number, typ protowire.Type, entry []byte) bool {
+		if num != featureMapField || typ != protowire.BytesType {
+			return false
+		}
+		return checkFields(entry, func(num protowire.Number, typ protowire.Type, value []byte) bool {
+			switch {
+			case num == mapKeyField && typ == protowire.BytesType:
+				return true
+			case num == mapValueField && typ == protowire.BytesType:
+				return checkValue(value)
+			}
+			return false
+		})
+	})
+}
+
+// Field numbers of the Feature kind oneof.
+const (
+	bytesListField = 1
+	floatListField = 2
+	int64ListField = 3
+)
+
+func checkFeature(data []byte) bool {
+	return checkFields(data, func(num protowire.Number, typ protowire.Type, list []byte) bool {
+		if typ != protowire.BytesType {
+			return false
+		}
+		switch num {
+		case bytesListField:
+			return checkFields(list, func(num protowire.Number, typ protowire.Type, _ []byte) bool {
+				return num == 1 && typ == protowire.BytesType
+			})
+		case floatListField:
+			return checkFields(list, func(num protowire.Number, typ protowire.Type, value []byte) bool {
+				return num == 1 && (typ == protowire.Fixed32Type || typ == protowire.BytesType && len(value)%4 == 0)
+			})
+		case int64ListField:
+			return checkFields(list, func(num protowire.Number, typ protowire.Type, value []byte) bool {
+				return num == 1 && (typ == protowire.VarintType || typ == protowire.BytesType && checkVarints(value))
+			})
+		}
+		return false
+	})
+}
+
+func checkFeatureList(data []byte) bool {
+	return checkFields(data, func(num protowire.Number, typ protowire.Type, feature []byte) bool {
+		return num == 1 && typ == protowire.BytesType && checkFeature(feature)
+	})
+}
+
+// checkVarints reports whether data is a packed list of varints.
+func checkVarints(data []byte) bool {
+	for len(data) > 0 {
+		_, n := protowire.ConsumeVarint(data)
+		if n < 0 {
+			return false
+		}
+		data = data[n:]
+	}
+	return true
+}
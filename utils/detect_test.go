@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDetectRecordType(t *testing.T) {
+	marshal := func(m proto.Message) []byte {
+		data, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	features := &protobuf.Features{Feature: map[string]*protobuf.Feature{
+		"age":   {Kind: &protobuf.Feature_Int64List{Int64List: &protobuf.Int64List{Value: []int64{29, 300}}}},
+		"score": {Kind: &protobuf.Feature_FloatList{FloatList: &protobuf.FloatList{Value: []float32{0.5}}}},
+		"movie": {Kind: &protobuf.Feature_BytesList{BytesList: &protobuf.BytesList{Value: [][]byte{[]byte("Heat")}}}},
+		"empty": {},
+	}}
+	example := marshal(&protobuf.Example{Features: features})
+	contextOnly := marshal(&protobuf.SequenceExample{Context: features})
+	sequence := marshal(&protobuf.SequenceExample{
+		Context: features,
+		FeatureLists: &protobuf.FeatureLists{FeatureList: map[string]*protobuf.FeatureList{
+			"movies": {Feature: []*protobuf.Feature{features.Feature["movie"], features.Feature["movie"]}},
+		}},
+	})
+	// A message of another type, with a string in field 1.
+	other := []byte{0x0a, 0x03, 'a', 'b', 'c'}
+
+	var tests = []struct {
+		desc    string
+		records [][]byte
+		want    string
+		err     bool
+	}{
+		{"examples", [][]byte{example, example}, RecordExample, false},
+		{"no records", nil, RecordExample, false},
+		{"context only", [][]byte{contextOnly}, RecordExample, false},
+		{"sequence examples", [][]byte{contextOnly, sequence}, RecordSequenceExample, false},
+		{"other message", [][]byte{example, other}, "", true},
+		{"truncated", [][]byte{example[:len(example)-1]}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := DetectRecordType(tt.records)
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("%s: got %q, %v, want %q, error %v", tt.desc, got, err, tt.want, tt.err)
+		}
+	}
+}
@@ -1,18 +1,30 @@
 package utils
 
 import (
+	"cmp"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	protobuf "github.com/emla2805/tfr/protobuf"
 	"google.golang.org/protobuf/proto"
 	pref "google.golang.org/protobuf/reflect/protoreflect"
+	"math"
 	"math/bits"
-	"sort"
+	"slices"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 )
 
 type jsonWriter struct {
-	buf []byte
+	buf  []byte
+	opts MarshalOptions
+	// feature is the name of the feature or feature list being marshaled.
+	feature string
+	// entries holds the entries of the maps being marshaled, one slice per
+	// level of nesting reused from one record to the next.
+	entries [][]mapEntry
+	depth   int
 }
 
 func (w *jsonWriter) write(s string) {
@@ -28,13 +40,83 @@ func (w *jsonWriter) writeString(s string) error {
 }
 
 func Marshal(m proto.Message) ([]byte, error) {
-	w := jsonWriter{}
-	err := w.marshalMessage(m.ProtoReflect())
+	return MarshalOptions{}.Marshal(m)
+}
+
+// MarshalOptions configure how records are marshaled.
+type MarshalOptions struct {
+	// DecodeTensors reports whether the bytes values of a feature are
+	// TensorProtos, serialized by tf.io.serialize_tensor, to decode.
+	DecodeTensors func(feature string) bool
+	// DetectTensors decodes the bytes values of any feature that parse as
+	// serialized TensorProtos.
+	DetectTensors bool
+	// NestedExamples reports whether the bytes values of a feature are
+	// serialized Examples to marshal inline, as in the Example-in-Example
+	// format of TF-Ranking.
+	NestedExamples func(feature string) bool
+	// Resolver resolves the types of google.protobuf.Any messages,
+	// protoregistry.GlobalTypes when nil.
+	Resolver TypeResolver
+}
+
+// Marshal marshals m with the options.
+func (o MarshalOptions) Marshal(m proto.Message) ([]byte, error) {
+	w := jsonWriter{opts: o}
+	err := w.marshalRecord(m, nil)
 	return w.buf, err
 }
 
+// RecordMeta describes where a record was read from.
+type RecordMeta struct {
+	File   string
+	Index  int
+	Offset int64
+	Length int
+	CRCOK  bool
+}
+
+// MarshalWithMeta marshals m wrapped in an envelope that carries meta, i.e.
+// {"_meta":{"file":...,"index":...,"offset":...,"length":...,"crc_ok":...},"record":{...}}.
+func MarshalWithMeta(m proto.Message, meta RecordMeta) ([]byte, error) {
+	return MarshalOptions{}.MarshalWithMeta(m, meta)
+}
+
+// MarshalWithMeta marshals m with the options, wrapped with meta.
+func (o MarshalOptions) MarshalWithMeta(m proto.Message, meta RecordMeta) ([]byte, error) {
+	w := jsonWriter{opts: o}
+	err := w.marshalRecord(m, &meta)
+	return w.buf, err
+}
+
+// marshalRecord marshals m, wrapped with meta unless it is nil.
+func (w *jsonWriter) marshalRecord(m proto.Message, meta *RecordMeta) error {
+	if meta == nil {
+		return w.marshalMessage(m.ProtoReflect())
+	}
+	w.write(`{"_meta":{"file":`)
+	if err := w.writeString(meta.File); err != nil {
+		return err
+	}
+	w.buf = append(w.buf, `,"index":`...)
+	w.buf = strconv.AppendInt(w.buf, int64(meta.Index), 10)
+	w.buf = append(w.buf, `,"offset":`...)
+	w.buf = strconv.AppendInt(w.buf, meta.Offset, 10)
+	w.buf = append(w.buf, `,"length":`...)
+	w.buf = strconv.AppendInt(w.buf, int64(meta.Length), 10)
+	w.buf = append(w.buf, `,"crc_ok":`...)
+	w.buf = strconv.AppendBool(w.buf, meta.CRCOK)
+	w.write(`},"record":`)
+	err := w.marshalMessage(m.ProtoReflect())
+	w.write(`}`)
+	return err
+}
+
 // marshalMessage marshals the given protoreflect.Message.
 func (w *jsonWriter) marshalMessage(m pref.Message) error {
+	if marshal, ok := wellKnownTypes[m.Descriptor().FullName()]; ok {
+		return marshal(w, m)
+	}
 	if err := w.marshalFields(m); err != nil {
 		return err
 	}
@@ -42,6 +124,20 @@ func (w *jsonWriter) marshalMessage(m pref.Message) error {
 	return nil
 }
 
+// exampleTypes are the messages of Examples and SequenceExamples, whose
+// unset messages are written empty.
+var exampleTypes = map[pref.FullName]bool{
+	"tensorflow.Example":         true,
+	"tensorflow.SequenceExample": true,
+	"tensorflow.Features":        true,
+	"tensorflow.Feature":         true,
+	"tensorflow.FeatureList":     true,
+	"tensorflow.FeatureLists":    true,
+	"tensorflow.BytesList":       true,
+	"tensorflow.FloatList":       true,
+	"tensorflow.Int64List":       true,
+}
+
 // marshalFields marshals the fields in the given protoreflect.Message.
 func (w *jsonWriter) marshalFields(m pref.Message) error {
 	messageDesc := m.Descriptor()
@@ -49,6 +145,7 @@ func (w *jsonWriter) marshalFields(m pref.Message) error {
 	w.write("{")
 	defer w.write("}")
 	firstField := true
+	emitUnset := exampleTypes[messageDesc.FullName()]
 
 	// Marshal out known fields.
 	fieldDescs := messageDesc.Fields()
@@ -62,6 +159,9 @@ func (w *jsonWriter) marshalFields(m pref.Message) error {
 			}
 		} else {
 			i++
+			if fd.HasPresence() && !m.Has(fd) && !emitUnset {
+				continue // unset messages and proto2 optional fields
+			}
 		}
 
 		val := m.Get(fd)
@@ -118,21 +218,44 @@ type mapEntry struct {
 // marshalMap marshals given protoreflect.Map.
 func (w *jsonWriter) marshalMap(mmap pref.Map, fd pref.FieldDescriptor) error {
 	// Get a sorted list based on keyType first.
-	entries := make([]mapEntry, 0, mmap.Len())
+	if w.depth == len(w.entries) {
+		w.entries = append(w.entries, nil)
+	}
+	entries := w.entries[w.depth][:0]
 	mmap.Range(func(key pref.MapKey, val pref.Value) bool {
 		entries = append(entries, mapEntry{key: key, value: val})
 		return true
 	})
 	sortMap(fd.MapKey().Kind(), entries)
+	w.entries[w.depth] = entries
+	w.depth++
+	defer func() {
+		w.depth--
+		clear(entries)
+	}()
 
 	w.write(`{`)
 	defer w.write(`}`)
 	comma := ""
 
+	// Remember the feature names for decoding tensors.
+	features := false
+	if md := fd.MapValue().Message(); md != nil {
+		name := md.FullName()
+		features = name == "tensorflow.Feature" || name == "tensorflow.FeatureList"
+	}
+	defer func(feature string) { w.feature = feature }(w.feature)
+
 	// Write out sorted list.
 	for _, entry := range entries {
 		w.write(comma)
-		w.write(`"` + entry.key.String() + `":`)
+		if err := w.writeString(entry.key.String()); err != nil {
+			return err
+		}
+		w.write(`:`)
+		if features {
+			w.feature = entry.key.String()
+		}
 		if err := w.marshalSingular(entry.value, fd.MapValue()); err != nil {
 			return err
 		}
@@ -143,17 +266,17 @@ func (w *jsonWriter) marshalMap(mmap pref.Map, fd pref.FieldDescriptor) error {
 
 // sortMap orders list based on value of key field for deterministic ordering.
 func sortMap(keyKind pref.Kind, values []mapEntry) {
-	sort.Slice(values, func(i, j int) bool {
+	slices.SortFunc(values, func(a, b mapEntry) int {
 		switch keyKind {
 		case pref.Int32Kind, pref.Sint32Kind, pref.Sfixed32Kind,
 			pref.Int64Kind, pref.Sint64Kind, pref.Sfixed64Kind:
-			return values[i].key.Int() < values[j].key.Int()
+			return cmp.Compare(a.key.Int(), b.key.Int())
 
 		case pref.Uint32Kind, pref.Fixed32Kind,
 			pref.Uint64Kind, pref.Fixed64Kind:
-			return values[i].key.Uint() < values[j].key.Uint()
+			return cmp.Compare(a.key.Uint(), b.key.Uint())
 		}
-		return values[i].key.String() < values[j].key.String()
+		return strings.Compare(a.key.String(), b.key.String())
 	})
 }
 
@@ -165,14 +288,37 @@ func (w *jsonWriter) marshalSingular(val pref.Value, fd pref.FieldDescriptor) er
 	}
 
 	switch kind := fd.Kind(); kind {
-	case pref.Int64Kind:
-		w.write(val.String())
+	case pref.BoolKind:
+		w.write(strconv.FormatBool(val.Bool()))
+
+	case pref.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(val.Enum()); ev != nil {
+			w.write(`"` + string(ev.Name()) + `"`)
+		} else {
+			w.write(strconv.FormatInt(int64(val.Enum()), 10))
+		}
+
+	case pref.Int32Kind, pref.Sint32Kind, pref.Sfixed32Kind,
+		pref.Int64Kind, pref.Sint64Kind, pref.Sfixed64Kind:
+		w.write(strconv.FormatInt(val.Int(), 10))
+
+	case pref.Uint32Kind, pref.Fixed32Kind,
+		pref.Uint64Kind, pref.Fixed64Kind:
+		w.write(strconv.FormatUint(val.Uint(), 10))
 
 	case pref.FloatKind:
-		w.write(val.String())
+		w.writeFloat(val.Float(), 32)
+
+	case pref.DoubleKind:
+		w.writeFloat(val.Float(), 64)
+
+	case pref.StringKind:
+		if err := w.writeString(val.String()); err != nil {
+			return err
+		}
 
 	case pref.BytesKind:
-		if err := w.writeString(string(val.Bytes())); err != nil {
+		if err := w.marshalBytes(val.Bytes(), fd); err != nil {
 			return err
 		}
 
@@ -187,6 +333,129 @@ func (w *jsonWriter) marshalSingular(val pref.Value, fd pref.FieldDescriptor) er
 	return nil
 }
 
+// marshalBytes writes a bytes value of a feature as a string, or as an
+// Example or a tensor when it is a value of a feature whose Examples or
+// tensors are decoded. Other bytes fields are base64 encoded, as protojson
+// does.
+func (w *jsonWriter) marshalBytes(b []byte, fd pref.FieldDescriptor) error {
+	if fd.FullName() != "tensorflow.BytesList.value" {
+		w.write(`"` + base64.StdEncoding.EncodeToString(b) + `"`)
+		return nil
+	}
+	if w.feature != "" {
+		if w.opts.NestedExamples != nil && w.opts.NestedExamples(w.feature) {
+			example := &protobuf.Example{}
+			if err := proto.Unmarshal(b, example); err != nil {
+				return fmt.Errorf("feature %s: cannot decode nested Example: %v", w.feature, err)
+			}
+			return w.marshalMessage(example.ProtoReflect())
+		}
+		required := w.opts.DecodeTensors != nil && w.opts.DecodeTensors(w.feature)
+		if required || w.opts.DetectTensors {
+			t, err := DecodeTensor(b)
+			if err == nil {
+				return w.marshalTensor(t)
+			}
+			if required {
+				return fmt.Errorf("feature %s: cannot decode tensor: %v", w.feature, err)
+			}
+		}
+	}
+	return w.writeString(string(b))
+}
+
+// marshalTensor writes a tensor as its dtype, shape and values in row-major
+// order. Complex values are [real, imaginary] pairs and strings that are not
+// valid UTF-8 are base64 encoded.
+func (w *jsonWriter) marshalTensor(t *Tensor) error {
+	w.write(`{"dtype":"` + t.DType.String() + `","shape":[`)
+	for i, dim := range t.Shape {
+		if i > 0 {
+			w.write(",")
+		}
+		w.write(strconv.FormatInt(dim, 10))
+	}
+	w.write(`],"values":[`)
+	switch values := t.Values.(type) {
+	case []float32:
+		for i, v := range values {
+			w.comma(i)
+			w.writeFloat(float64(v), 32)
+		}
+	case []float64:
+		for i, v := range values {
+			w.comma(i)
+			w.writeFloat(v, 64)
+		}
+	case []int64:
+		for i, v := range values {
+			w.comma(i)
+			w.write(strconv.FormatInt(v, 10))
+		}
+	case []uint64:
+		for i, v := range values {
+			w.comma(i)
+			w.write(strconv.FormatUint(v, 10))
+		}
+	case []bool:
+		for i, v := range values {
+			w.comma(i)
+			w.write(strconv.FormatBool(v))
+		}
+	case []complex64:
+		for i, v := range values {
+			w.comma(i)
+			w.write("[")
+			w.writeFloat(float64(real(v)), 32)
+			w.write(",")
+			w.writeFloat(float64(imag(v)), 32)
+			w.write("]")
+		}
+	case []complex128:
+		for i, v := range values {
+			w.comma(i)
+			w.write("[")
+			w.writeFloat(real(v), 64)
+			w.write(",")
+			w.writeFloat(imag(v), 64)
+			w.write("]")
+		}
+	case [][]byte:
+		for i, v := range values {
+			w.comma(i)
+			if !utf8.Valid(v) {
+				w.write(`"` + base64.StdEncoding.EncodeToString(v) + `"`)
+			} else if err := w.writeString(string(v)); err != nil {
+				return err
+			}
+		}
+	}
+	w.write("]}")
+	return nil
+}
+
+// comma writes the separator before the i-th item of a list.
+func (w *jsonWriter) comma(i int) {
+	if i > 0 {
+		w.write(",")
+	}
+}
+
+// writeFloat writes a float of bitSize bits, with NaN and infinities as
+// strings like protojson does.
+func (w *jsonWriter) writeFloat(f float64, bitSize int) {
+	switch {
+	case math.IsNaN(f):
+		w.write(`"NaN"`)
+	case math.IsInf(f, 1):
+		w.write(`"Infinity"`)
+	case math.IsInf(f, -1):
+		w.write(`"-Infinity"`)
+	default:
+		w.buf = strconv.AppendFloat(w.buf, f, 'g', -1, bitSize)
+	}
+}
+
 // Sentinel error used for indicating invalid UTF-8.
 var errInvalidUTF8 = errors.New("invalid UTF-8")
 
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Framing is how an Encoder separates records.
+type Framing int
+
+const (
+	// JSONLines writes one record per line.
+	JSONLines Framing = iota
+	// JSONArray writes the records as the elements of a JSON array, one
+	// per line.
+	JSONArray
+)
+
+// DefaultBufferSize is the number of bytes an Encoder buffers before
+// writing them out.
+const DefaultBufferSize = 64 << 10
+
+// Encoder writes records as JSON to an io.Writer. It encodes records into a
+// buffer reused from one record to the next and writes it out once it holds
+// more than the buffer size, so that encoding millions of records costs
+// neither an allocation nor a write per record. Call Close once done to
+// write out the end of the output.
+type Encoder struct {
+	w       io.Writer
+	jw      jsonWriter
+	framing Framing
+	size    int
+	records int
+	err     error
+}
+
+// NewEncoder returns an Encoder writing records marshaled with opts to w,
+// as JSON Lines.
+func NewEncoder(w io.Writer, opts MarshalOptions) *Encoder {
+	return &Encoder{w: w, jw: jsonWriter{opts: opts}, size: DefaultBufferSize}
+}
+
+// SetFraming sets how records are separated. It must be called before the
+// first record is encoded.
+func (e *Encoder) SetFraming(framing Framing) {
+	e.framing = framing
+}
+
+// SetBufferSize sets the number of bytes buffered before they are written
+// out. With 0, each record is written as soon as it is encoded.
+func (e *Encoder) SetBufferSize(size int) {
+	e.size = size
+}
+
+// Encode writes m.
+func (e *Encoder) Encode(m proto.Message) error {
+	return e.encode(m, nil)
+}
+
+// EncodeWithMeta writes m wrapped with meta, as MarshalWithMeta does.
+func (e *Encoder) EncodeWithMeta(m proto.Message, meta RecordMeta) error {
+	return e.encode(m, &meta)
+}
+
+func (e *Encoder) encode(m proto.Message, meta *RecordMeta) error {
+	if e.err != nil {
+		return e.err
+	}
+	// Records that fail to encode are dropped from the buffer.
+	start := len(e.jw.buf)
+	switch {
+	case e.framing == JSONArray && e.records == 0:
+		e.jw.write("[\n")
+	case e.framing == JSONArray:
+		e.jw.write(",\n")
+	}
+	if err := e.jw.marshalRecord(m, meta); err != nil {
+		e.jw.buf = e.jw.buf[:start]
+		return err
+	}
+	if e.framing == JSONLines {
+		e.jw.write("\n")
+	}
+	e.records++
+	if len(e.jw.buf) >= e.size {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush writes out the buffered records.
+func (e *Encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.jw.buf) > 0 {
+		_, e.err = e.w.Write(e.jw.buf)
+		e.jw.buf = e.jw.buf[:0]
+	}
+	return e.err
+}
+
+// Close ends the JSON array, if any, and writes out the buffered records.
+// It does not close the underlying writer, and the Encoder must not be used
+// afterwards.
+func (e *Encoder) Close() error {
+	if e.err == nil && e.framing == JSONArray {
+		if e.records == 0 {
+			e.jw.write("[")
+		} else {
+			e.jw.write("\n")
+		}
+		e.jw.write("]\n")
+	}
+	return e.Flush()
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// countingWriter counts the writes made to it.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestEncoder(t *testing.T) {
+	records := []proto.Message{
+		protobuf.NewExample().Int64("age", 29).Build(),
+		protobuf.NewExample().Strings("movie", "invalid \xff").Build(),
+		protobuf.NewExample().Float("rating", 9.5).Build(),
+	}
+	lines := "{\"features\":{\"feature\":{\"age\":{\"int64List\":{\"value\":[29]}}}}}\n" +
+		"{\"features\":{\"feature\":{\"rating\":{\"floatList\":{\"value\":[9.5]}}}}}\n"
+
+	var tests = []struct {
+		desc    string
+		framing Framing
+		records []proto.Message
+		want    string
+	}{
+		{"json lines", JSONLines, records, lines},
+		{"json array", JSONArray, records, "[\n" +
+			"{\"features\":{\"feature\":{\"age\":{\"int64List\":{\"value\":[29]}}}}},\n" +
+			"{\"features\":{\"feature\":{\"rating\":{\"floatList\":{\"value\":[9.5]}}}}}\n]\n"},
+		{"empty array", JSONArray, nil, "[]\n"},
+		{"empty lines", JSONLines, nil, ""},
+	}
+	for _, tt := range tests {
+		var buf countingWriter
+		enc := NewEncoder(&buf, MarshalOptions{})
+		enc.SetFraming(tt.framing)
+		for i, m := range tt.records {
+			// Records failing to encode are left out.
+			if err := enc.Encode(m); (err != nil) != (i == 1) {
+				t.Errorf("%s: record %d: got error %v", tt.desc, i, err)
+			}
+		}
+		if buf.writes != 0 {
+			t.Errorf("%s: got %d writes before Close, want 0", tt.desc, buf.writes)
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+
+	// Without buffering, each record is written as it is encoded.
+	var buf countingWriter
+	enc := NewEncoder(&buf, MarshalOptions{})
+	enc.SetBufferSize(0)
+	meta := RecordMeta{File: "a.tfrecord", Index: 1, Offset: 42, Length: 7, CRCOK: true}
+	if err := enc.EncodeWithMeta(records[0], meta); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := MarshalWithMeta(records[0], meta)
+	if buf.writes != 1 || buf.String() != string(want)+"\n" {
+		t.Errorf("got %q in %d writes, want %s", buf.String(), buf.writes, want)
+	}
+}
+
+// ratingExamples returns Examples like those of the README, which are
+// typical of the small records dumped by the million.
+func ratingExamples(n int) []proto.Message {
+	records := make([]proto.Message, n)
+	for i := range records {
+		records[i] = protobuf.NewExample().
+			Int64("age", int64(20+i%50)).
+			Strings("movie", "The Shawshank Redemption", "Fight Club").
+			Float("movie_ratings", 9, 9.7).
+			Strings("user", fmt.Sprintf("user-%d", i)).
+			Build()
+	}
+	return records
+}
+
+// BenchmarkMarshalFprintln writes records as the CLI did before the
+// Encoder, marshaling each one to a fresh buffer printed as a string.
+func BenchmarkMarshalFprintln(b *testing.B) {
+	records := ratingExamples(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := Marshal(records[i%len(records)])
+		if err != nil {
+			b.Fatal(err)
+		}
+		fmt.Fprintln(io.Discard, string(data))
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	records := ratingExamples(100)
+	enc := NewEncoder(io.Discard, MarshalOptions{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := enc.Encode(records[i%len(records)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		b.Fatal(err)
+	}
+}
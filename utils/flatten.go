@@ -0,0 +1,104 @@
+package utils
+
+import (
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// FeatureValues unwraps the kind oneof of f and returns its values as
+// []int64, []float32 or []string (one string per bytes value). A feature
+// without a kind returns nil.
+func FeatureValues(f *protobuf.Feature) interface{} {
+	switch kind := f.GetKind().(type) {
+	case *protobuf.Feature_Int64List:
+		return kind.Int64List.GetValue()
+	case *protobuf.Feature_FloatList:
+		return kind.FloatList.GetValue()
+	case *protobuf.Feature_BytesList:
+		values := make([]string, len(kind.BytesList.GetValue()))
+		for i, b := range kind.BytesList.GetValue() {
+			values[i] = string(b)
+		}
+		return values
+	}
+	return nil
+}
+
+// FlattenFeatures returns the unwrapped values of every feature in fs keyed
+// by feature name.
+func FlattenFeatures(fs *protobuf.Features) map[string]interface{} {
+	flat := make(map[string]interface{}, len(fs.GetFeature()))
+	for name, f := range fs.GetFeature() {
+		flat[name] = FeatureValues(f)
+	}
+	return flat
+}
+
+// FlattenFeatureLists returns, for every feature list in fl, the unwrapped
+// values of each of its steps keyed by feature list name.
+func FlattenFeatureLists(fl *protobuf.FeatureLists) map[string][]interface{} {
+	flat := make(map[string][]interface{}, len(fl.GetFeatureList()))
+	for name, list := range fl.GetFeatureList() {
+		steps := make([]interface{}, len(list.GetFeature()))
+		for i, f := range list.GetFeature() {
+			steps[i] = FeatureValues(f)
+		}
+		flat[name] = steps
+	}
+	return flat
+}
+
+// FlattenRecord returns the flattened features of an Example, or the context
+// features and feature lists of a SequenceExample, keyed by name.
+func FlattenRecord(m proto.Message) map[string]interface{} {
+	switch m := m.(type) {
+	case *protobuf.Example:
+		return FlattenFeatures(m.GetFeatures())
+	case *protobuf.SequenceExample:
+		fields := FlattenFeatures(m.GetContext())
+		for name, steps := range FlattenFeatureLists(m.GetFeatureLists()) {
+			fields[name] = steps
+		}
+		return fields
+	}
+	return map[string]interface{}{}
+}
+
+// PruneFeatures removes the features of an Example, or the context features
+// and feature lists of a SequenceExample, that are not selected by matcher.
+func PruneFeatures(m proto.Message, matcher *FeatureMatcher) {
+	switch m := m.(type) {
+	case *protobuf.Example:
+		pruneMap(m.GetFeatures().GetFeature(), matcher)
+	case *protobuf.SequenceExample:
+		pruneMap(m.GetContext().GetFeature(), matcher)
+		for name := range m.GetFeatureLists().GetFeatureList() {
+			if !matcher.Match(name) {
+				delete(m.FeatureLists.FeatureList, name)
+			}
+		}
+	}
+}
+
+func pruneMap(features map[string]*protobuf.Feature, matcher *FeatureMatcher) {
+	for name := range features {
+		if !matcher.Match(name) {
+			delete(features, name)
+		}
+	}
+}
+
+// valueCount returns the number of values in an unwrapped feature.
+func valueCount(values interface{}) int {
+	switch v := values.(type) {
+	case []int64:
+		return len(v)
+	case []float32:
+		return len(v)
+	case []string:
+		return len(v)
+	case []interface{}:
+		return len(v)
+	}
+	return 0
+}
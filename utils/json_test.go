@@ -3,6 +3,12 @@ package utils
 import (
 	protobuf "github.com/emla2805/tfr/protobuf"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"math"
 	"testing"
 )
 
@@ -109,6 +115,44 @@ var marshalingTests = []struct {
 }{
 	{"example object", example, exampleJSON},
 	{"sequenceExample object", sequenceExample, sequenceExampleJSON},
+	{"empty example", &protobuf.Example{}, `{"features":{"feature":{}}}`},
+	{"empty sequence example", &protobuf.SequenceExample{}, `{"context":{"feature":{}},"featureLists":{"featureList":{}}}`},
+	{"example list with context", &protobuf.ExampleListWithContext{
+		Examples: []*protobuf.Example{{Features: &protobuf.Features{Feature: map[string]*protobuf.Feature{"age": age}}}},
+		Context:  &protobuf.Example{Features: &protobuf.Features{Feature: map[string]*protobuf.Feature{"movie": movie}}},
+	}, `{"examples":[{"features":{"feature":{"age":{"int64List":{"value":[29]}}}}}],` +
+		`"context":{"features":{"feature":{"movie":{"bytesList":{"value":["The Shawshank Redemption","Fight Club"]}}}}}}`},
+	{"float edge cases", &protobuf.FloatList{Value: []float32{float32(math.NaN()), float32(math.Inf(-1)), 1e-7}},
+		`{"value":["NaN","-Infinity",1e-07]}`},
+	{"escaped map key", &protobuf.Features{Feature: map[string]*protobuf.Feature{`a"b`: {}}},
+		`{"feature":{"a\"b":{}}}`},
+	{"timestamp", &timestamppb.Timestamp{Seconds: 1600000000, Nanos: 120000000}, `"2020-09-13T12:26:40.120Z"`},
+	{"duration", &durationpb.Duration{Seconds: -1, Nanos: -500000000}, `"-1.500s"`},
+	{"wrapper", wrapperspb.UInt64(18446744073709551615), `18446744073709551615`},
+	{"bytes wrapper", wrapperspb.Bytes([]byte{0xff, 'x'}), `"/3g="`},
+	{"struct", mustStruct(map[string]interface{}{"a": []interface{}{1.5, "x", true, nil}}),
+		`{"a":[1.5,"x",true,null]}`},
+	{"any", mustAny(wrapperspb.String("x")), `{"@type":"type.googleapis.com/google.protobuf.StringValue","value":"x"}`},
+	{"any message", mustAny(&protobuf.Int64List{Value: []int64{1}}),
+		`{"@type":"type.googleapis.com/tensorflow.Int64List","value":[1]}`},
+	{"unknown any", &anypb.Any{TypeUrl: "example.com/Unknown", Value: []byte{1, 2}},
+		`{"@type":"example.com/Unknown","value":"AQI="}`},
+}
+
+func mustStruct(v map[string]interface{}) *structpb.Struct {
+	s, err := structpb.NewStruct(v)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func mustAny(m proto.Message) *anypb.Any {
+	a, err := anypb.New(m)
+	if err != nil {
+		panic(err)
+	}
+	return a
 }
 
 func TestMarshaling(t *testing.T) {
@@ -122,3 +166,72 @@ func TestMarshaling(t *testing.T) {
 		}
 	}
 }
+
+func TestMarshalingTensors(t *testing.T) {
+	tensor, err := proto.Marshal(&protobuf.TensorProto{
+		Dtype:         protobuf.DataType_DT_FLOAT,
+		TensorShape:   shape(2, 2),
+		TensorContent: []byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0xc0, 0, 0, 0xc0, 0x7f, 0, 0, 0, 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := &protobuf.Example{Features: &protobuf.Features{Feature: map[string]*protobuf.Feature{
+		"image": {Kind: &protobuf.Feature_BytesList{BytesList: &protobuf.BytesList{Value: [][]byte{tensor}}}},
+		"movie": movie,
+	}}}
+	tensorJSON := `{"features":{"feature":{` +
+		`"image":{"bytesList":{"value":[{"dtype":"DT_FLOAT","shape":[2,2],"values":[1,-2,"NaN",0]}]}},` +
+		`"movie":{"bytesList":{"value":["The Shawshank Redemption","Fight Club"]}}}}}`
+
+	var tests = []struct {
+		desc string
+		opts MarshalOptions
+		json string
+		err  bool
+	}{
+		{"named", MarshalOptions{DecodeTensors: func(f string) bool { return f == "image" }}, tensorJSON, false},
+		{"detected", MarshalOptions{DetectTensors: true}, tensorJSON, false},
+		{"not a tensor", MarshalOptions{DecodeTensors: func(f string) bool { return true }}, "", true},
+	}
+	for _, tt := range tests {
+		json, err := tt.opts.Marshal(record)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.desc)
+			}
+		} else if err != nil {
+			t.Errorf("%s: marshaling error: %v", tt.desc, err)
+		} else if string(json) != tt.json {
+			t.Errorf("%s:\ngot:  %s\nwant: %s", tt.desc, json, tt.json)
+		}
+	}
+}
+
+func TestMarshalingNestedExamples(t *testing.T) {
+	nested, err := proto.Marshal(&protobuf.Example{Features: &protobuf.Features{Feature: map[string]*protobuf.Feature{"age": age}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := &protobuf.Example{Features: &protobuf.Features{Feature: map[string]*protobuf.Feature{
+		"serialized_examples": {Kind: &protobuf.Feature_BytesList{BytesList: &protobuf.BytesList{Value: [][]byte{nested, nested}}}},
+		"movie":               movie,
+	}}}
+	opts := MarshalOptions{NestedExamples: func(f string) bool { return f == "serialized_examples" }}
+	json, err := opts.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nestedJSON := `{"features":{"feature":{"age":{"int64List":{"value":[29]}}}}}`
+	want := `{"features":{"feature":{` +
+		`"movie":{"bytesList":{"value":["The Shawshank Redemption","Fight Club"]}},` +
+		`"serialized_examples":{"bytesList":{"value":[` + nestedJSON + `,` + nestedJSON + `]}}}}}`
+	if string(json) != want {
+		t.Errorf("\ngot:  %s\nwant: %s", json, want)
+	}
+
+	opts.NestedExamples = func(f string) bool { return f == "movie" }
+	if _, err := opts.Marshal(record); err == nil {
+		t.Error("expected an error decoding text as an Example")
+	}
+}
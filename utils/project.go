@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// FeatureMatcher selects features by name. Patterns are globs where "*"
+// matches any run of characters, including "/", or regular expressions
+// written between slashes, e.g. "/^image\/.*/".
+type FeatureMatcher struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	names   map[string]bool
+}
+
+// NewFeatureMatcher returns a matcher selecting features that match any of
+// the include patterns, or every feature when there are none, and none of
+// the exclude patterns.
+func NewFeatureMatcher(include, exclude []string) (*FeatureMatcher, error) {
+	m := &FeatureMatcher{}
+	for _, p := range include {
+		re, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		m.include = append(m.include, re)
+	}
+	for _, p := range exclude {
+		re, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		m.exclude = append(m.exclude, re)
+	}
+	return m, nil
+}
+
+// WithNames returns a copy of m that also selects the features called names,
+// whatever the patterns.
+func (m *FeatureMatcher) WithNames(names ...string) *FeatureMatcher {
+	with := *m
+	with.names = make(map[string]bool, len(m.names)+len(names))
+	for name := range m.names {
+		with.names[name] = true
+	}
+	for _, name := range names {
+		with.names[name] = true
+	}
+	return &with
+}
+
+// Match reports whether the feature called name is selected.
+func (m *FeatureMatcher) Match(name string) bool {
+	if m.names[name] {
+		return true
+	}
+	if len(m.include) > 0 && !matchAny(m.include, name) {
+		return false
+	}
+	return !matchAny(m.exclude, name)
+}
+
+func matchAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func compilePattern(p string) (*regexp.Regexp, error) {
+	if len(p) >= 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+		re, err := regexp.Compile(p[1 : len(p)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature pattern %q: %v", p, err)
+		}
+		return re, nil
+	}
+	return regexp.Compile("^" + globToRegexp(p) + "$")
+}
+
+// globToRegexp translates the wildcards "*" and "?" and character classes
+// such as "[a-z]" to regexp syntax, quoting everything else.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+var errMalformed = errors.New("malformed record")
+
+// Field numbers of the messages projected on the wire.
+const (
+	exampleFeaturesField            = 1
+	sequenceExampleContextField     = 1
+	sequenceExampleFeatureListField = 2
+	featureMapField                 = 1
+	mapKeyField                     = 1
+	mapValueField                   = 2
+)
+
+// ProjectExample rewrites a serialized Example keeping only the features
+// selected by m. Unselected feature values are skipped over on the wire and
+// never decoded.
+func ProjectExample(data []byte, m *FeatureMatcher) ([]byte, error) {
+	return projectMessage(data, m, exampleFeaturesField)
+}
+
+// ProjectSequenceExample rewrites a serialized SequenceExample keeping only
+// the context features and feature lists selected by m.
+func ProjectSequenceExample(data []byte, m *FeatureMatcher) ([]byte, error) {
+	return projectMessage(data, m, sequenceExampleContextField, sequenceExampleFeatureListField)
+}
+
+// projectMessage projects the feature maps held in the given fields of data,
+// copying all other fields as is.
+func projectMessage(data []byte, m *FeatureMatcher, fields ...protowire.Number) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, errMalformed
+		}
+		size := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if size < 0 {
+			return nil, errMalformed
+		}
+		field := data[:n+size]
+		data = data[n+size:]
+
+		if typ != protowire.BytesType || !containsNumber(fields, num) {
+			out = append(out, field...)
+			continue
+		}
+		value, _ := protowire.ConsumeBytes(field[n:])
+		projected, err := projectFeatureMap(value, m)
+		if err != nil {
+			return nil, err
+		}
+		out = protowire.AppendTag(out, num, protowire.BytesType)
+		out = protowire.AppendBytes(out, projected)
+	}
+	return out, nil
+}
+
+// projectFeatureMap filters the entries of a serialized Features or
+// FeatureLists message by key.
+func projectFeatureMap(data []byte, m *FeatureMatcher) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, errMalformed
+		}
+		size := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if size < 0 {
+			return nil, errMalformed
+		}
+		field := data[:n+size]
+		data = data[n+size:]
+
+		if num == featureMapField && typ == protowire.BytesType {
+			entry, _ := protowire.ConsumeBytes(field[n:])
+			key, _, ok := consumeMapEntry(entry)
+			if !ok {
+				return nil, errMalformed
+			}
+			if !m.Match(string(key)) {
+				continue
+			}
+		}
+		out = append(out, field...)
+	}
+	return out, nil
+}
+
+func containsNumber(nums []protowire.Number, num protowire.Number) bool {
+	for _, n := range nums {
+		if n == num {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+var matcherTests = []struct {
+	include, exclude []string
+	name             string
+	want             bool
+}{
+	{nil, nil, "age", true},
+	{[]string{"age"}, nil, "age", true},
+	{[]string{"age"}, nil, "agent", false},
+	{[]string{"image/*"}, nil, "image/encoded", true},
+	{[]string{"*"}, []string{"image/*"}, "image/encoded", false},
+	{[]string{"movie?"}, nil, "movies", true},
+	{[]string{"[a-c]ge"}, nil, "age", true},
+	{[]string{"[!a-c]ge"}, nil, "age", false},
+	{[]string{"/^mov.*s$/"}, nil, "movie_ratings", true},
+	{nil, []string{"/rating/"}, "movie_ratings", false},
+	{[]string{"a.e"}, nil, "age", false},
+}
+
+func TestFeatureMatcher(t *testing.T) {
+	for _, tt := range matcherTests {
+		m, err := NewFeatureMatcher(tt.include, tt.exclude)
+		if err != nil {
+			t.Fatalf("%v %v: %v", tt.include, tt.exclude, err)
+		}
+		if got := m.Match(tt.name); got != tt.want {
+			t.Errorf("include %v exclude %v: Match(%q) = %v, want %v", tt.include, tt.exclude, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	m, _ := NewFeatureMatcher([]string{"age", "movie*"}, []string{"*_ratings"})
+
+	data, _ := proto.Marshal(example)
+	projected, err := ProjectExample(data, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &protobuf.Example{}
+	if err := proto.Unmarshal(projected, got); err != nil {
+		t.Fatal(err)
+	}
+	want := &protobuf.Example{Features: &protobuf.Features{
+		Feature: map[string]*protobuf.Feature{"age": age, "movie": movie},
+	}}
+	if !proto.Equal(got, want) {
+		t.Errorf("Example:\ngot:  %v\nwant: %v", got, want)
+	}
+
+	data, _ = proto.Marshal(sequenceExample)
+	projected, err = ProjectSequenceExample(data, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotSeq := &protobuf.SequenceExample{}
+	if err := proto.Unmarshal(projected, gotSeq); err != nil {
+		t.Fatal(err)
+	}
+	wantSeq := &protobuf.SequenceExample{
+		Context: sequenceExample.Context,
+		FeatureLists: &protobuf.FeatureLists{
+			FeatureList: map[string]*protobuf.FeatureList{"movie_names": movieNames},
+		},
+	}
+	if !proto.Equal(gotSeq, wantSeq) {
+		t.Errorf("SequenceExample:\ngot:  %v\nwant: %v", gotSeq, wantSeq)
+	}
+}
@@ -0,0 +1,327 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ellipsis       = "…"
+	columnSep      = "  "
+	minColumnWidth = 4
+)
+
+// TableOptions control how records are laid out by WriteTable and
+// WriteTransposed. Zero values select the defaults.
+type TableOptions struct {
+	// Width is the maximum line width, usually the terminal width.
+	// Zero means lines are never cut to fit.
+	Width int
+	// MaxItems is the number of list values shown per cell before the
+	// rest are elided. Defaults to 3.
+	MaxItems int
+	// MaxCell is the maximum width of a single cell. Defaults to 40.
+	MaxCell int
+}
+
+func (o TableOptions) withDefaults() TableOptions {
+	if o.MaxItems <= 0 {
+		o.MaxItems = 3
+	}
+	if o.MaxCell <= 0 {
+		o.MaxCell = 40
+	}
+	return o
+}
+
+// WriteTable writes records as an aligned table with one row per record and
+// one column per feature. Column headers carry a hint of the number of values
+// in the column, e.g. "movie [1-3]".
+func WriteTable(w io.Writer, records []proto.Message, opts TableOptions) error {
+	labels := make([]string, len(records))
+	for i := range labels {
+		labels[i] = strconv.Itoa(i)
+	}
+	return WriteLabeledTable(w, records, labels, opts)
+}
+
+// WriteLabeledTable is WriteTable with the rows labeled in the "#" column by
+// labels rather than by their position, e.g. with the index of the records
+// in their file.
+func WriteLabeledTable(w io.Writer, records []proto.Message, labels []string, opts TableOptions) error {
+	opts = opts.withDefaults()
+
+	rows := make([]map[string]interface{}, len(records))
+	for i, m := range records {
+		rows[i] = FlattenRecord(m)
+	}
+	names := columnNames(rows)
+
+	header := []string{"#"}
+	for _, name := range names {
+		header = append(header, name+" "+lengthHint(rows, name))
+	}
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = []string{labels[i]}
+		for _, name := range names {
+			cell := ""
+			if values, ok := row[name]; ok {
+				cell = formatValues(values, opts.MaxItems)
+			}
+			cells[i] = append(cells[i], cell)
+		}
+	}
+
+	widths := make([]int, len(header))
+	for c, h := range header {
+		widths[c] = textWidth(h)
+		for _, row := range cells {
+			if n := textWidth(row[c]); n > widths[c] {
+				widths[c] = n
+			}
+		}
+		if widths[c] > opts.MaxCell {
+			widths[c] = opts.MaxCell
+		}
+	}
+	widths, elided := fitColumns(widths, opts.Width)
+
+	if err := writeRow(w, header, widths, elided); err != nil {
+		return err
+	}
+	for _, row := range cells {
+		if err := writeRow(w, row, widths, elided); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTransposed writes records as blocks of feature/value rows, one block
+// per record.
+func WriteTransposed(w io.Writer, records []proto.Message, opts TableOptions) error {
+	for i, m := range records {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if err := WriteRecordBlock(w, m, fmt.Sprintf("record %d", i), opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteRecordBlock writes a record as a block of feature/value rows under
+// title, as WriteTransposed does, so that records can be written one at a
+// time as they are read.
+func WriteRecordBlock(w io.Writer, m proto.Message, title string, opts TableOptions) error {
+	opts = opts.withDefaults()
+
+	row := FlattenRecord(m)
+	names := columnNames([]map[string]interface{}{row})
+
+	labels := make([]string, len(names))
+	labelWidth := 0
+	for j, name := range names {
+		labels[j] = fmt.Sprintf("%s [%d]", name, valueCount(row[name]))
+		if n := textWidth(labels[j]); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	valueWidth := 0
+	if opts.Width > 0 {
+		if labelWidth > opts.Width/2 {
+			labelWidth = opts.Width / 2
+		}
+		valueWidth = opts.Width - labelWidth - len(columnSep)
+	}
+
+	if _, err := fmt.Fprintln(w, title); err != nil {
+		return err
+	}
+	for j, name := range names {
+		value := formatValues(row[name], opts.MaxItems)
+		if valueWidth > 0 {
+			value = truncate(value, valueWidth)
+		}
+		line := pad(truncate(labels[j], labelWidth), labelWidth) + columnSep + value
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnNames returns the sorted union of feature names in rows.
+func columnNames(rows []map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, row := range rows {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lengthHint describes the number of values of a feature across rows, either
+// as a single count or as a "min-max" range.
+func lengthHint(rows []map[string]interface{}, name string) string {
+	min, max := -1, -1
+	for _, row := range rows {
+		values, ok := row[name]
+		if !ok {
+			continue
+		}
+		n := valueCount(values)
+		if min < 0 || n < min {
+			min = n
+		}
+		if n > max {
+			max = n
+		}
+	}
+	if min == max {
+		return fmt.Sprintf("[%d]", min)
+	}
+	return fmt.Sprintf("[%d-%d]", min, max)
+}
+
+// fitColumns shrinks the widest columns until the table fits in width. When
+// even minimum-width columns do not fit, trailing columns are dropped and
+// elided is set.
+func fitColumns(widths []int, width int) (fitted []int, elided bool) {
+	if width <= 0 {
+		return widths, false
+	}
+	total := func(ws []int) int {
+		n := 0
+		for _, w := range ws {
+			n += w
+		}
+		return n + len(columnSep)*(len(ws)-1)
+	}
+	for total(widths) > width {
+		widest := 0
+		for c := range widths {
+			if widths[c] > widths[widest] {
+				widest = c
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+	if total(widths) <= width {
+		return widths, false
+	}
+	for len(widths) > 1 && total(widths)+len(columnSep)+textWidth(ellipsis) > width {
+		widths = widths[:len(widths)-1]
+	}
+	return widths, true
+}
+
+func writeRow(w io.Writer, cells []string, widths []int, elided bool) error {
+	var b strings.Builder
+	for c, width := range widths {
+		if c > 0 {
+			b.WriteString(columnSep)
+		}
+		b.WriteString(pad(truncate(cells[c], width), width))
+	}
+	if elided {
+		b.WriteString(columnSep + ellipsis)
+	}
+	_, err := fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	return err
+}
+
+// formatValues renders unwrapped feature values for display. A single value
+// is shown bare, lists are bracketed and cut after maxItems values.
+func formatValues(values interface{}, maxItems int) string {
+	var items []string
+	switch v := values.(type) {
+	case []int64:
+		for _, x := range v {
+			items = append(items, strconv.FormatInt(x, 10))
+		}
+	case []float32:
+		for _, x := range v {
+			items = append(items, strconv.FormatFloat(float64(x), 'g', -1, 32))
+		}
+	case []string:
+		for _, x := range v {
+			items = append(items, formatBytes(x))
+		}
+	case []interface{}:
+		for _, step := range v {
+			s := formatValues(step, maxItems)
+			if valueCount(step) == 1 {
+				s = "[" + s + "]"
+			}
+			items = append(items, s)
+		}
+		return joinItems(items, maxItems)
+	}
+	if len(items) == 1 {
+		return items[0]
+	}
+	return joinItems(items, maxItems)
+}
+
+func joinItems(items []string, maxItems int) string {
+	if len(items) > maxItems {
+		items = append(items[:maxItems:maxItems], ellipsis)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// formatBytes shows printable UTF-8 as is and quotes anything else.
+func formatBytes(s string) string {
+	if !utf8.ValidString(s) {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if !strconv.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+func textWidth(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// truncate cuts s to at most width runes, marking the cut with an ellipsis.
+func truncate(s string, width int) string {
+	if textWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + ellipsis
+}
+
+func pad(s string, width int) string {
+	if n := textWidth(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var tableTests = []struct {
+	desc    string
+	records []proto.Message
+	opts    TableOptions
+	write   func(*bytes.Buffer, []proto.Message, TableOptions) error
+	want    string
+}{
+	{
+		"table",
+		[]proto.Message{example},
+		TableOptions{},
+		func(b *bytes.Buffer, r []proto.Message, o TableOptions) error { return WriteTable(b, r, o) },
+		"#  age [1]  movie [2]                               movie_ratings [2]\n" +
+			"0  29       [The Shawshank Redemption, Fight Club]  [9, 9.7]\n",
+	},
+	{
+		"table fit to width",
+		[]proto.Message{example},
+		TableOptions{Width: 40},
+		func(b *bytes.Buffer, r []proto.Message, o TableOptions) error { return WriteTable(b, r, o) },
+		"#  age [1]  movie [2]      movie_rating…\n" +
+			"0  29       [The Shawsha…  [9, 9.7]\n",
+	},
+	{
+		"table elides columns",
+		[]proto.Message{example},
+		TableOptions{Width: 12},
+		func(b *bytes.Buffer, r []proto.Message, o TableOptions) error { return WriteTable(b, r, o) },
+		"#  age…  …\n" +
+			"0  29    …\n",
+	},
+	{
+		"labeled table",
+		[]proto.Message{example},
+		TableOptions{},
+		func(b *bytes.Buffer, r []proto.Message, o TableOptions) error {
+			return WriteLabeledTable(b, r, []string{"shard-1:12"}, o)
+		},
+		"#           age [1]  movie [2]                               movie_ratings [2]\n" +
+			"shard-1:12  29       [The Shawshank Redemption, Fight Club]  [9, 9.7]\n",
+	},
+	{
+		"record block",
+		[]proto.Message{example},
+		TableOptions{MaxItems: 1},
+		func(b *bytes.Buffer, r []proto.Message, o TableOptions) error {
+			return WriteRecordBlock(b, r[0], "record 7 (shard-1)", o)
+		},
+		"record 7 (shard-1)\n" +
+			"age [1]            29\n" +
+			"movie [2]          [The Shawshank Redemption, …]\n" +
+			"movie_ratings [2]  [9, …]\n",
+	},
+	{
+		"transposed",
+		[]proto.Message{example},
+		TableOptions{MaxItems: 1},
+		func(b *bytes.Buffer, r []proto.Message, o TableOptions) error { return WriteTransposed(b, r, o) },
+		"record 0\n" +
+			"age [1]            29\n" +
+			"movie [2]          [The Shawshank Redemption, …]\n" +
+			"movie_ratings [2]  [9, …]\n",
+	},
+}
+
+func TestTable(t *testing.T) {
+	for _, tt := range tableTests {
+		var buf bytes.Buffer
+		if err := tt.write(&buf, tt.records, tt.opts); err != nil {
+			t.Errorf("%s: error: %v", tt.desc, err)
+		} else if got := buf.String(); got != tt.want {
+			t.Errorf("%s:\ngot:\n%v\nwant:\n%v", tt.desc, got, tt.want)
+		}
+	}
+}
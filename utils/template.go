@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// TemplateFuncs are the helper functions available to record templates in
+// addition to the text/template builtins such as len, index and printf.
+var TemplateFuncs = template.FuncMap{
+	"base64": templateBase64,
+	"join":   templateJoin,
+	"json":   templateJSON,
+}
+
+// TemplateData returns the flattened view of a record that is passed to a
+// record template. Examples expose their features under "Features" and
+// SequenceExamples expose "Context" and "FeatureLists". Feature values are
+// []int64, []float32 or []string. "Index" holds the ordinal of the record.
+func TemplateData(m proto.Message, index int) map[string]interface{} {
+	data := map[string]interface{}{"Index": index}
+	switch m := m.(type) {
+	case *protobuf.Example:
+		data["Features"] = FlattenFeatures(m.GetFeatures())
+	case *protobuf.SequenceExample:
+		data["Context"] = FlattenFeatures(m.GetContext())
+		data["FeatureLists"] = FlattenFeatureLists(m.GetFeatureLists())
+	}
+	return data
+}
+
+// templateBase64 encodes a bytes value, or each value of a bytes list, as
+// standard base64.
+func templateBase64(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return base64.StdEncoding.EncodeToString([]byte(v)), nil
+	case []string:
+		encoded := make([]string, len(v))
+		for i, s := range v {
+			encoded[i] = base64.StdEncoding.EncodeToString([]byte(s))
+		}
+		return encoded, nil
+	}
+	return nil, fmt.Errorf("base64: unsupported type %T", v)
+}
+
+// templateJoin joins the values of any list with sep. Taking the list last
+// allows it to be piped, as in {{.Features.movie | join ", "}}.
+func templateJoin(sep string, list interface{}) (string, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return "", fmt.Errorf("join: unsupported type %T", list)
+	}
+	items := make([]string, v.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(items, sep), nil
+}
+
+func templateJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var templateTests = []struct {
+	desc string
+	pb   proto.Message
+	text string
+	want string
+}{
+	{"feature list", example, `{{.Features.age}}`, `[29]`},
+	{"index", example, `{{index .Features.movie 1}}`, `Fight Club`},
+	{"join", example, `{{.Features.movie_ratings | join ";"}}`, `9;9.7`},
+	{"json", example, `{{json .Features.movie}}`, `["The Shawshank Redemption","Fight Club"]`},
+	{"base64", example, `{{base64 (index .Features.movie 1)}}`, `RmlnaHQgQ2x1Yg==`},
+	{"record index", example, `{{.Index}}`, `0`},
+	{"context", sequenceExample, `{{index .Context.age 0}}`, `29`},
+	{"feature lists", sequenceExample, `{{len .FeatureLists.actors}} {{index .FeatureLists.actors 1 | join ","}}`,
+		`2 Brad Pitt,Edward Norton,Helena Bonham Carter`},
+}
+
+func TestTemplate(t *testing.T) {
+	for _, tt := range templateTests {
+		tmpl, err := template.New(tt.desc).Funcs(TemplateFuncs).Parse(tt.text)
+		if err != nil {
+			t.Errorf("%s: parse error: %v", tt.desc, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, TemplateData(tt.pb, 0)); err != nil {
+			t.Errorf("%s: execute error: %v", tt.desc, err)
+		} else if got := buf.String(); got != tt.want {
+			t.Errorf("%s:\ngot:  %v\nwant: %v", tt.desc, got, tt.want)
+		}
+	}
+}
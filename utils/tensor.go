@@ -0,0 +1,320 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// Tensor is a decoded TensorProto.
+type Tensor struct {
+	DType protobuf.DataType
+	Shape []int64
+	// Values holds the elements in row-major order: []float32 for DT_FLOAT,
+	// DT_HALF and DT_BFLOAT16, []float64 for DT_DOUBLE, []uint64 for
+	// DT_UINT64, []int64 for the other integer types, quantized ones
+	// included, []bool, [][]byte for DT_STRING, and []complex64 or
+	// []complex128.
+	Values interface{}
+}
+
+// DecodeTensor parses a TensorProto serialized by tf.io.serialize_tensor.
+// Only data with a dtype and a shape parse, so that most other bytes do not.
+func DecodeTensor(data []byte) (*Tensor, error) {
+	t := &protobuf.TensorProto{}
+	if err := proto.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	if t.Dtype == protobuf.DataType_DT_INVALID || t.TensorShape == nil {
+		return nil, fmt.Errorf("not a serialized tensor")
+	}
+	return ParseTensor(t)
+}
+
+// ParseTensor returns the elements of t, from its packed tensor_content or
+// its typed *_val fields. As in TensorFlow, typed values shorter than the
+// tensor are padded with the last one, or zero when there are none, up to
+// maxPadding elements. Shapes the data cannot hold are rejected before any
+// allocation.
+func ParseTensor(t *protobuf.TensorProto) (*Tensor, error) {
+	if t.GetTensorShape().GetUnknownRank() {
+		return nil, fmt.Errorf("tensor of unknown rank")
+	}
+	tensor := &Tensor{DType: t.Dtype, Shape: []int64{}}
+	n := 1
+	for _, dim := range t.GetTensorShape().GetDim() {
+		if dim.Size < 0 {
+			return nil, fmt.Errorf("tensor of unknown shape")
+		}
+		tensor.Shape = append(tensor.Shape, dim.Size)
+		if dim.Size > 0 && n > math.MaxInt/int(min(dim.Size, math.MaxInt)) {
+			return nil, fmt.Errorf("tensor of shape %v overflows", tensor.Shape)
+		}
+		n *= int(dim.Size)
+	}
+
+	content := t.TensorContent
+	if t.Dtype == protobuf.DataType_DT_STRING {
+		// Strings are never packed.
+		content = nil
+	}
+	var err error
+	switch t.Dtype {
+	case protobuf.DataType_DT_FLOAT:
+		var values []float32
+		if values, err = newValues[float32](n, content, 4, len(t.FloatVal)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 4, func(i int, b []byte) {
+				values[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+			})
+		} else {
+			err = fillValues(len(t.FloatVal), n, func(i, j int) { values[i] = t.FloatVal[j] })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_HALF, protobuf.DataType_DT_BFLOAT16:
+		toFloat := halfToFloat32
+		if t.Dtype == protobuf.DataType_DT_BFLOAT16 {
+			toFloat = bfloat16ToFloat32
+		}
+		var values []float32
+		if values, err = newValues[float32](n, content, 2, len(t.HalfVal)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 2, func(i int, b []byte) {
+				values[i] = toFloat(binary.LittleEndian.Uint16(b))
+			})
+		} else {
+			err = fillValues(len(t.HalfVal), n, func(i, j int) { values[i] = toFloat(uint16(t.HalfVal[j])) })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_DOUBLE:
+		var values []float64
+		if values, err = newValues[float64](n, content, 8, len(t.DoubleVal)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 8, func(i int, b []byte) {
+				values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+			})
+		} else {
+			err = fillValues(len(t.DoubleVal), n, func(i, j int) { values[i] = t.DoubleVal[j] })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_INT32, protobuf.DataType_DT_INT16, protobuf.DataType_DT_INT8,
+		protobuf.DataType_DT_UINT16, protobuf.DataType_DT_UINT8,
+		protobuf.DataType_DT_QINT32, protobuf.DataType_DT_QINT16, protobuf.DataType_DT_QUINT16,
+		protobuf.DataType_DT_QINT8, protobuf.DataType_DT_QUINT8:
+		size, decode := intDecoder(t.Dtype)
+		var values []int64
+		if values, err = newValues[int64](n, content, size, len(t.IntVal)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, size, func(i int, b []byte) { values[i] = decode(b) })
+		} else {
+			err = fillValues(len(t.IntVal), n, func(i, j int) { values[i] = int64(t.IntVal[j]) })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_INT64:
+		var values []int64
+		if values, err = newValues[int64](n, content, 8, len(t.Int64Val)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 8, func(i int, b []byte) {
+				values[i] = int64(binary.LittleEndian.Uint64(b))
+			})
+		} else {
+			err = fillValues(len(t.Int64Val), n, func(i, j int) { values[i] = t.Int64Val[j] })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_UINT32:
+		var values []int64
+		if values, err = newValues[int64](n, content, 4, len(t.Uint32Val)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 4, func(i int, b []byte) {
+				values[i] = int64(binary.LittleEndian.Uint32(b))
+			})
+		} else {
+			err = fillValues(len(t.Uint32Val), n, func(i, j int) { values[i] = int64(t.Uint32Val[j]) })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_UINT64:
+		var values []uint64
+		if values, err = newValues[uint64](n, content, 8, len(t.Uint64Val)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 8, func(i int, b []byte) {
+				values[i] = binary.LittleEndian.Uint64(b)
+			})
+		} else {
+			err = fillValues(len(t.Uint64Val), n, func(i, j int) { values[i] = t.Uint64Val[j] })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_BOOL:
+		var values []bool
+		if values, err = newValues[bool](n, content, 1, len(t.BoolVal)); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 1, func(i int, b []byte) { values[i] = b[0] != 0 })
+		} else {
+			err = fillValues(len(t.BoolVal), n, func(i, j int) { values[i] = t.BoolVal[j] })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_COMPLEX64:
+		var values []complex64
+		if values, err = newValues[complex64](n, content, 8, len(t.ScomplexVal)/2); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 8, func(i int, b []byte) {
+				values[i] = complex(math.Float32frombits(binary.LittleEndian.Uint32(b)),
+					math.Float32frombits(binary.LittleEndian.Uint32(b[4:])))
+			})
+		} else {
+			v := t.ScomplexVal
+			err = fillValues(len(v)/2, n, func(i, j int) { values[i] = complex(v[2*j], v[2*j+1]) })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_COMPLEX128:
+		var values []complex128
+		if values, err = newValues[complex128](n, content, 16, len(t.DcomplexVal)/2); err != nil {
+			break
+		}
+		if len(content) > 0 {
+			err = decodeContent(content, n, 16, func(i int, b []byte) {
+				values[i] = complex(math.Float64frombits(binary.LittleEndian.Uint64(b)),
+					math.Float64frombits(binary.LittleEndian.Uint64(b[8:])))
+			})
+		} else {
+			v := t.DcomplexVal
+			err = fillValues(len(v)/2, n, func(i, j int) { values[i] = complex(v[2*j], v[2*j+1]) })
+		}
+		tensor.Values = values
+
+	case protobuf.DataType_DT_STRING:
+		var values [][]byte
+		if values, err = newValues[[]byte](n, content, 0, len(t.StringVal)); err != nil {
+			break
+		}
+		err = fillValues(len(t.StringVal), n, func(i, j int) { values[i] = t.StringVal[j] })
+		tensor.Values = values
+
+	default:
+		return nil, fmt.Errorf("unsupported tensor dtype %v", t.Dtype)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tensor, nil
+}
+
+// maxPadding is the largest number of elements of a tensor padded from
+// fewer typed values, so that a few bytes cannot claim a huge tensor.
+const maxPadding = 1 << 20
+
+// newValues allocates the n elements of a tensor after checking that its
+// data holds them: content packs elements of size bytes, and otherwise count
+// typed values are padded up to at most maxPadding elements.
+func newValues[T any](n int, content []byte, size, count int) ([]T, error) {
+	switch {
+	case len(content) > 0 && (n > len(content)/size || len(content) != n*size):
+		return nil, fmt.Errorf("tensor content of %d bytes, expected %d elements of %d bytes", len(content), n, size)
+	case len(content) == 0 && n > count && n > maxPadding:
+		return nil, fmt.Errorf("tensor of %d elements padded from %d values", n, count)
+	}
+	return make([]T, n), nil
+}
+
+// decodeContent calls decode with each of the n elements of size bytes of
+// a packed tensor_content.
+func decodeContent(content []byte, n, size int, decode func(i int, b []byte)) error {
+	if len(content) != n*size {
+		return fmt.Errorf("tensor content of %d bytes, expected %d", len(content), n*size)
+	}
+	for i := 0; i < n; i++ {
+		decode(i, content[i*size:(i+1)*size])
+	}
+	return nil
+}
+
+// fillValues calls set with the index of each of the n elements of a
+// tensor and the index of its value among count typed values.
+func fillValues(count, n int, set func(i, j int)) error {
+	if count > n {
+		return fmt.Errorf("%d tensor values, expected %d", count, n)
+	}
+	if count == 0 {
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		j := i
+		if j >= count {
+			j = count - 1
+		}
+		set(i, j)
+	}
+	return nil
+}
+
+// intDecoder returns the size and a little-endian decoder of the elements
+// of an integer dtype stored in tensor_content.
+func intDecoder(dtype protobuf.DataType) (int, func([]byte) int64) {
+	switch dtype {
+	case protobuf.DataType_DT_INT16, protobuf.DataType_DT_QINT16:
+		return 2, func(b []byte) int64 { return int64(int16(binary.LittleEndian.Uint16(b))) }
+	case protobuf.DataType_DT_UINT16, protobuf.DataType_DT_QUINT16:
+		return 2, func(b []byte) int64 { return int64(binary.LittleEndian.Uint16(b)) }
+	case protobuf.DataType_DT_INT8, protobuf.DataType_DT_QINT8:
+		return 1, func(b []byte) int64 { return int64(int8(b[0])) }
+	case protobuf.DataType_DT_UINT8, protobuf.DataType_DT_QUINT8:
+		return 1, func(b []byte) int64 { return int64(b[0]) }
+	}
+	return 4, func(b []byte) int64 { return int64(int32(binary.LittleEndian.Uint32(b))) }
+}
+
+// halfToFloat32 converts an IEEE 754 half precision float.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+	switch {
+	case exp == 0x1f:
+		// Infinities and NaN.
+		return math.Float32frombits(sign | 0xff<<23 | mant<<13)
+	case exp == 0 && mant == 0:
+		return math.Float32frombits(sign)
+	case exp == 0:
+		// Subnormal numbers are normal in single precision.
+		f := float32(mant) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
+// bfloat16ToFloat32 converts a bfloat16, the upper half of a float32.
+func bfloat16ToFloat32(b uint16) float32 {
+	return math.Float32frombits(uint32(b) << 16)
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+)
+
+func shape(dims ...int64) *protobuf.TensorShapeProto {
+	s := &protobuf.TensorShapeProto{}
+	for _, d := range dims {
+		s.Dim = append(s.Dim, &protobuf.TensorShapeProto_Dim{Size: d})
+	}
+	return s
+}
+
+var parseTensorTests = []struct {
+	desc   string
+	tensor *protobuf.TensorProto
+	shape  []int64
+	values interface{}
+}{
+	{"float content", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_FLOAT, TensorShape: shape(2),
+		TensorContent: []byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0xc0}}, []int64{2}, []float32{1, -2}},
+	{"scalar float val", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_FLOAT, TensorShape: shape(),
+		FloatVal: []float32{0.5}}, []int64{}, []float32{0.5}},
+	{"padded with the last value", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_INT32, TensorShape: shape(2, 2),
+		IntVal: []int32{1, 2}}, []int64{2, 2}, []int64{1, 2, 2, 2}},
+	{"zeros without values", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_DOUBLE, TensorShape: shape(3)},
+		[]int64{3}, []float64{0, 0, 0}},
+	{"int8 content", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_INT8, TensorShape: shape(2),
+		TensorContent: []byte{0xff, 7}}, []int64{2}, []int64{-1, 7}},
+	{"uint16 content", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_UINT16, TensorShape: shape(1),
+		TensorContent: []byte{0xff, 0xff}}, []int64{1}, []int64{65535}},
+	{"int64 content", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_INT64, TensorShape: shape(1),
+		TensorContent: []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}, []int64{1}, []int64{-2}},
+	{"uint64 val", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_UINT64, TensorShape: shape(1),
+		Uint64Val: []uint64{math.MaxUint64}}, []int64{1}, []uint64{math.MaxUint64}},
+	{"half val", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_HALF, TensorShape: shape(4),
+		HalfVal: []int32{0x3c00, 0xc000, 0x0001, 0x7c00}}, []int64{4},
+		[]float32{1, -2, 5.9604645e-08, float32(math.Inf(1))}},
+	{"bfloat16 content", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_BFLOAT16, TensorShape: shape(1),
+		TensorContent: []byte{0x80, 0x3f}}, []int64{1}, []float32{1}},
+	{"bool content", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_BOOL, TensorShape: shape(2),
+		TensorContent: []byte{1, 0}}, []int64{2}, []bool{true, false}},
+	{"complex64 val", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_COMPLEX64, TensorShape: shape(1),
+		ScomplexVal: []float32{1, -1}}, []int64{1}, []complex64{complex(1, -1)}},
+	{"string val", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_STRING, TensorShape: shape(2),
+		StringVal: [][]byte{[]byte("a"), []byte("b")}}, []int64{2}, [][]byte{[]byte("a"), []byte("b")}},
+}
+
+func TestParseTensor(t *testing.T) {
+	for _, tt := range parseTensorTests {
+		tensor, err := ParseTensor(tt.tensor)
+		if err != nil {
+			t.Errorf("%s: %v", tt.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(tensor.Shape, tt.shape) || !reflect.DeepEqual(tensor.Values, tt.values) {
+			t.Errorf("%s: got %v %v, want %v %v", tt.desc, tensor.Shape, tensor.Values, tt.shape, tt.values)
+		}
+	}
+}
+
+var parseTensorErrorTests = []struct {
+	desc   string
+	tensor *protobuf.TensorProto
+}{
+	{"short content", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_FLOAT, TensorShape: shape(2), TensorContent: []byte{0, 0, 0x80, 0x3f}}},
+	{"too many values", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_INT64, TensorShape: shape(1), Int64Val: []int64{1, 2}}},
+	{"unknown dimension", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_FLOAT, TensorShape: shape(-1)}},
+	{"resource", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_RESOURCE, TensorShape: shape()}},
+	{"overflowing shape", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_FLOAT, TensorShape: shape(3<<61, 3), FloatVal: []float32{1}}},
+	{"huge padding", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_INT64, TensorShape: shape(1 << 40), Int64Val: []int64{1}}},
+	{"huge content shape", &protobuf.TensorProto{Dtype: protobuf.DataType_DT_DOUBLE, TensorShape: shape(1 << 60), TensorContent: make([]byte, 16)}},
+}
+
+func TestParseTensorErrors(t *testing.T) {
+	for _, tt := range parseTensorErrorTests {
+		if _, err := ParseTensor(tt.tensor); err == nil {
+			t.Errorf("%s: expected an error", tt.desc)
+		}
+	}
+}
+
+func TestDecodeTensorRejectsText(t *testing.T) {
+	for _, s := range []string{"", "The Shawshank Redemption", "hello"} {
+		if _, err := DecodeTensor([]byte(s)); err == nil {
+			t.Errorf("%q decoded as a tensor", s)
+		}
+	}
+}
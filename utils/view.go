@@ -0,0 +1,352 @@
+package utils
+
+import (
+	"bytes"
+	"iter"
+	"math"
+	"slices"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ExampleView reads the features of a serialized Example without decoding
+// it. Reset only indexes the names of the features, whose values are
+// decoded when asked for, so that reading a label out of a record holding
+// images costs next to nothing. Bytes values alias the serialized record.
+//
+// A view can be reset to read record after record without allocating.
+type ExampleView struct {
+	// entries are sorted by name, holding the last of the entries with the
+	// same name on the wire as parsers do.
+	entries []viewEntry
+}
+
+type viewEntry struct {
+	name    []byte
+	feature []byte
+}
+
+// NewExampleView returns a view of the serialized Example data.
+func NewExampleView(data []byte) (*ExampleView, error) {
+	v := &ExampleView{}
+	if err := v.Reset(data); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Reset makes v a view of the serialized Example data, keeping no
+// reference to the previous record.
+func (v *ExampleView) Reset(data []byte) error {
+	clear(v.entries)
+	v.entries = v.entries[:0]
+	ok := checkFields(data, func(num protowire.Number, typ protowire.Type, features []byte) bool {
+		if num != exampleFeaturesField {
+			return true
+		}
+		return typ == protowire.BytesType && checkFields(features, func(num protowire.Number, typ protowire.Type, entry []byte) bool {
+			if num != featureMapField {
+				return true
+			}
+			if typ != protowire.BytesType {
+				return false
+			}
+			name, feature, ok := consumeMapEntry(entry)
+			v.entries = append(v.entries, viewEntry{name, feature})
+			return ok
+		})
+	})
+	if !ok {
+		v.entries = v.entries[:0]
+		return errMalformed
+	}
+
+	slices.SortStableFunc(v.entries, func(a, b viewEntry) int {
+		return bytes.Compare(a.name, b.name)
+	})
+	deduped := v.entries[:0]
+	for i, e := range v.entries {
+		if i+1 < len(v.entries) && bytes.Equal(e.name, v.entries[i+1].name) {
+			continue
+		}
+		deduped = append(deduped, e)
+	}
+	v.entries = deduped
+	return nil
+}
+
+// Len returns the number of features.
+func (v *ExampleView) Len() int {
+	return len(v.entries)
+}
+
+// Feature returns the feature called name.
+func (v *ExampleView) Feature(name string) (FeatureView, bool) {
+	i, ok := slices.BinarySearchFunc(v.entries, name, func(e viewEntry, name string) int {
+		return compareName(e.name, name)
+	})
+	if !ok {
+		return FeatureView{}, false
+	}
+	return FeatureView{name: v.entries[i].name, data: v.entries[i].feature}, true
+}
+
+// All returns an iterator over the features by name, in name order.
+func (v *ExampleView) All() iter.Seq2[string, FeatureView] {
+	return func(yield func(string, FeatureView) bool) {
+		for _, e := range v.entries {
+			if !yield(string(e.name), FeatureView{name: e.name, data: e.feature}) {
+				return
+			}
+		}
+	}
+}
+
+// Flatten returns the values of the features called names, as FeatureValues
+// does, for filter expressions. Missing features are left out.
+func (v *ExampleView) Flatten(names []string) (map[string]interface{}, error) {
+	flat := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		f, ok := v.Feature(name)
+		if !ok {
+			continue
+		}
+		values, err := f.Values()
+		if err != nil {
+			return nil, err
+		}
+		flat[name] = values
+	}
+	return flat, nil
+}
+
+// compareName compares a and b like bytes.Compare, without converting b.
+func compareName(a []byte, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// consumeMapEntry returns the key and value of a serialized map entry of
+// strings to messages.
+func consumeMapEntry(entry []byte) (key, value []byte, ok bool) {
+	ok = checkFields(entry, func(num protowire.Number, typ protowire.Type, field []byte) bool {
+		switch num {
+		case mapKeyField:
+			key = field
+		case mapValueField:
+			value = field
+		default:
+			return true
+		}
+		return typ == protowire.BytesType
+	})
+	return key, value, ok
+}
+
+// FeatureView is a feature of an ExampleView.
+type FeatureView struct {
+	name []byte
+	data []byte
+}
+
+// lists returns the number of the kind of the feature and the part of its
+// serialization holding the lists of that kind: a kind replaces the lists
+// of any other one before it, as the oneof does when parsed.
+func (f FeatureView) lists() (protowire.Number, []byte, error) {
+	var kind protowire.Number
+	start := 0
+	for data := f.data; len(data) > 0; {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return 0, nil, errMalformed
+		}
+		size := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if size < 0 {
+			return 0, nil, errMalformed
+		}
+		if num >= bytesListField && num <= int64ListField {
+			if typ != protowire.BytesType {
+				return 0, nil, errMalformed
+			}
+			if num != kind {
+				kind, start = num, len(f.data)-len(data)
+			}
+		}
+		data = data[n+size:]
+	}
+	return kind, f.data[start:], nil
+}
+
+// Kind returns the kind of the feature: bytes, float, int64, or none when
+// no list is set.
+func (f FeatureView) Kind() string {
+	kind, _, _ := f.lists()
+	return kindName(kind)
+}
+
+func kindName(kind protowire.Number) string {
+	switch kind {
+	case bytesListField:
+		return "bytes"
+	case floatListField:
+		return "float"
+	case int64ListField:
+		return "int64"
+	}
+	return "none"
+}
+
+// eachValue calls fn with the value fields of the lists of the feature,
+// which must be of kind want unless want is 0.
+func (f FeatureView) eachValue(want protowire.Number, fn func(kind protowire.Number, typ protowire.Type, value []byte) bool) error {
+	kind, lists, err := f.lists()
+	if err != nil {
+		return err
+	}
+	if want != 0 && kind != want {
+		return &protobuf.KindError{Name: string(f.name), Kind: kindName(kind), Want: kindName(want)}
+	}
+	ok := checkFields(lists, func(num protowire.Number, typ protowire.Type, list []byte) bool {
+		if num != kind {
+			return true
+		}
+		return checkFields(list, func(num protowire.Number, typ protowire.Type, value []byte) bool {
+			return num != 1 || fn(kind, typ, value)
+		})
+	})
+	if !ok {
+		return errMalformed
+	}
+	return nil
+}
+
+// Count returns the number of values of the feature.
+func (f FeatureView) Count() (int, error) {
+	count := 0
+	err := f.eachValue(0, func(kind protowire.Number, typ protowire.Type, value []byte) bool {
+		switch {
+		case typ != protowire.BytesType || kind == bytesListField:
+			count++
+		case kind == floatListField:
+			count += len(value) / 4
+		default:
+			// Packed varints, which end with the bytes below 0x80.
+			for _, b := range value {
+				if b < 0x80 {
+					count++
+				}
+			}
+		}
+		return true
+	})
+	return count, err
+}
+
+// AppendInt64s appends the values of the int64 feature to dst.
+func (f FeatureView) AppendInt64s(dst []int64) ([]int64, error) {
+	err := f.eachValue(int64ListField, func(_ protowire.Number, typ protowire.Type, value []byte) bool {
+		switch typ {
+		case protowire.VarintType:
+			x, _ := protowire.ConsumeVarint(value)
+			dst = append(dst, int64(x))
+		case protowire.BytesType:
+			for len(value) > 0 {
+				x, n := protowire.ConsumeVarint(value)
+				if n < 0 {
+					return false
+				}
+				dst = append(dst, int64(x))
+				value = value[n:]
+			}
+		default:
+			return false
+		}
+		return true
+	})
+	return dst, err
+}
+
+// AppendFloats appends the values of the float feature to dst.
+func (f FeatureView) AppendFloats(dst []float32) ([]float32, error) {
+	err := f.eachValue(floatListField, func(_ protowire.Number, typ protowire.Type, value []byte) bool {
+		switch typ {
+		case protowire.Fixed32Type:
+			x, _ := protowire.ConsumeFixed32(value)
+			dst = append(dst, math.Float32frombits(x))
+		case protowire.BytesType:
+			if len(value)%4 != 0 {
+				return false
+			}
+			for ; len(value) > 0; value = value[4:] {
+				x, _ := protowire.ConsumeFixed32(value)
+				dst = append(dst, math.Float32frombits(x))
+			}
+		default:
+			return false
+		}
+		return true
+	})
+	return dst, err
+}
+
+// AppendBytes appends the values of the bytes feature to dst. They alias
+// the serialized record.
+func (f FeatureView) AppendBytes(dst [][]byte) ([][]byte, error) {
+	err := f.eachValue(bytesListField, func(_ protowire.Number, typ protowire.Type, value []byte) bool {
+		dst = append(dst, value)
+		return typ == protowire.BytesType
+	})
+	return dst, err
+}
+
+// Int64s returns the values of the int64 feature.
+func (f FeatureView) Int64s() ([]int64, error) {
+	return f.AppendInt64s(nil)
+}
+
+// Floats returns the values of the float feature.
+func (f FeatureView) Floats() ([]float32, error) {
+	return f.AppendFloats(nil)
+}
+
+// Bytes returns the values of the bytes feature, which alias the
+// serialized record.
+func (f FeatureView) Bytes() ([][]byte, error) {
+	return f.AppendBytes(nil)
+}
+
+// Values returns the values of the feature as []int64, []float32 or
+// []string, or nil when no list is set, as FeatureValues does.
+func (f FeatureView) Values() (interface{}, error) {
+	switch f.Kind() {
+	case "int64":
+		return f.Int64s()
+	case "float":
+		return f.Floats()
+	case "bytes":
+		values, err := f.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		strings := make([]string, len(values))
+		for i, b := range values {
+			strings[i] = string(b)
+		}
+		return strings, nil
+	}
+	return nil, nil
+}
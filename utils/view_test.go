@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// unpackedFeature serializes a Feature whose values are not packed, as some
+// writers other than TensorFlow produce them.
+func unpackedFeature(ints []int64, floats []float32) []byte {
+	var list []byte
+	for _, x := range ints {
+		list = protowire.AppendTag(list, 1, protowire.VarintType)
+		list = protowire.AppendVarint(list, uint64(x))
+	}
+	num := protowire.Number(int64ListField)
+	if floats != nil {
+		num = floatListField
+		for _, x := range floats {
+			list = protowire.AppendTag(list, 1, protowire.Fixed32Type)
+			list = protowire.AppendFixed32(list, math.Float32bits(x))
+		}
+	}
+	feature := protowire.AppendTag(nil, num, protowire.BytesType)
+	return protowire.AppendBytes(feature, list)
+}
+
+// appendFeature appends a features map entry to a serialized Example.
+func appendFeature(example []byte, name string, feature []byte) []byte {
+	entry := protowire.AppendTag(nil, mapKeyField, protowire.BytesType)
+	entry = protowire.AppendString(entry, name)
+	entry = protowire.AppendTag(entry, mapValueField, protowire.BytesType)
+	entry = protowire.AppendBytes(entry, feature)
+	features := protowire.AppendTag(nil, featureMapField, protowire.BytesType)
+	features = protowire.AppendBytes(features, entry)
+	example = protowire.AppendTag(example, exampleFeaturesField, protowire.BytesType)
+	return protowire.AppendBytes(example, features)
+}
+
+func TestExampleView(t *testing.T) {
+	example := protobuf.NewExample().
+		Int64("age", 29, -1).
+		Float("rating", 9.5, 7).
+		Strings("movie", "Heat", "Up").
+		Strings("empty").
+		Build()
+	example.Features.Feature["unset"] = &protobuf.Feature{}
+	data, err := proto.Marshal(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Entries repeated on the wire replace the earlier ones, and a kind
+	// replaces the lists of any other kind before it.
+	data = appendFeature(data, "clicks", unpackedFeature([]int64{1, 2}, nil))
+	data = appendFeature(data, "scores", unpackedFeature(nil, []float32{0.5}))
+	data = appendFeature(data, "age", unpackedFeature([]int64{30}, nil))
+	oneof := append(unpackedFeature([]int64{4}, nil), unpackedFeature(nil, []float32{1})...)
+	oneof = append(oneof, unpackedFeature([]int64{5}, nil)...)
+	oneof = append(oneof, unpackedFeature([]int64{6}, nil)...)
+	data = appendFeature(data, "oneof", oneof)
+
+	decoded := &protobuf.Example{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	want := FlattenRecord(decoded)
+
+	view, err := NewExampleView(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for name := range view.All() {
+		names = append(names, name)
+	}
+	wantNames := []string{"age", "clicks", "empty", "movie", "oneof", "rating", "scores", "unset"}
+	if !reflect.DeepEqual(names, wantNames) || view.Len() != len(wantNames) {
+		t.Errorf("got names %q, want %q", names, wantNames)
+	}
+	got, err := view.Flatten(append(names, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for name, f := range view.All() {
+		count, err := f.Count()
+		if wantCount := valueCount(want[name]); err != nil || count != wantCount {
+			t.Errorf("%s: got count %d, %v, want %d", name, count, err, wantCount)
+		}
+		if kind, wantKind := f.Kind(), decoded.Features.Feature[name].KindName(); kind != wantKind {
+			t.Errorf("%s: got kind %s, want %s", name, kind, wantKind)
+		}
+	}
+
+	age, _ := view.Feature("age")
+	var kindErr *protobuf.KindError
+	if _, err := age.Floats(); !errors.As(err, &kindErr) || kindErr.Name != "age" || kindErr.Want != "float" {
+		t.Errorf("got %v reading int64s as floats, want a KindError", err)
+	}
+	if _, err := NewExampleView(data[:len(data)-1]); err != errMalformed {
+		t.Errorf("got %v for a truncated record, want errMalformed", err)
+	}
+}
+
+// imageExample returns a serialized Example holding a few images of size
+// bytes along with a label and some numeric features, as image datasets do.
+// The images are ASCII for Marshal to write them.
+func imageExample(size int) []byte {
+	rng := rand.New(rand.NewSource(1))
+	images := make([][]byte, 4)
+	for i := range images {
+		images[i] = make([]byte, size)
+		for j := range images[i] {
+			images[i][j] = byte('a' + rng.Intn(26))
+		}
+	}
+	data, err := proto.Marshal(protobuf.NewExample().
+		Bytes("image/encoded", images...).
+		Strings("image/format", "jpeg", "jpeg", "jpeg", "jpeg").
+		Int64("image/height", 480, 480, 480, 480).
+		Int64("image/width", 640, 640, 640, 640).
+		Float("image/object/bbox", 0.1, 0.2, 0.3, 0.4).
+		Int64("label", 3).
+		Build())
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func BenchmarkUnmarshalMarshal(b *testing.B) {
+	data := imageExample(64 << 10)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		example := &protobuf.Example{}
+		if err := proto.Unmarshal(data, example); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := Marshal(example); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalFilter(b *testing.B) {
+	data := imageExample(64 << 10)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		example := &protobuf.Example{}
+		if err := proto.Unmarshal(data, example); err != nil {
+			b.Fatal(err)
+		}
+		if labels := FlattenRecord(example)["label"].([]int64); labels[0] != 3 {
+			b.Fatal(labels)
+		}
+	}
+}
+
+func BenchmarkViewFilter(b *testing.B) {
+	data := imageExample(64 << 10)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	var view ExampleView
+	for i := 0; i < b.N; i++ {
+		if err := view.Reset(data); err != nil {
+			b.Fatal(err)
+		}
+		f, _ := view.Feature("label")
+		if labels, err := f.Int64s(); err != nil || labels[0] != 3 {
+			b.Fatal(labels, err)
+		}
+	}
+}
+
+func BenchmarkViewCount(b *testing.B) {
+	data := imageExample(64 << 10)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	var view ExampleView
+	for i := 0; i < b.N; i++ {
+		if err := view.Reset(data); err != nil {
+			b.Fatal(err)
+		}
+		for _, f := range view.All() {
+			if _, err := f.Count(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// TypeResolver resolves the type URLs of google.protobuf.Any messages.
+type TypeResolver interface {
+	FindMessageByURL(url string) (pref.MessageType, error)
+}
+
+// wellKnownTypes marshal the well-known types to their JSON mapping rather
+// than field by field.
+var wellKnownTypes map[pref.FullName]func(w *jsonWriter, m pref.Message) error
+
+func init() {
+	wellKnownTypes = map[pref.FullName]func(w *jsonWriter, m pref.Message) error{
+		"google.protobuf.Any":         marshalAny,
+		"google.protobuf.Timestamp":   marshalTimestamp,
+		"google.protobuf.Duration":    marshalDuration,
+		"google.protobuf.FieldMask":   marshalFieldMask,
+		"google.protobuf.Empty":       marshalEmpty,
+		"google.protobuf.Struct":      marshalField("fields"),
+		"google.protobuf.ListValue":   marshalField("values"),
+		"google.protobuf.Value":       marshalKnownValue,
+		"google.protobuf.DoubleValue": marshalField("value"),
+		"google.protobuf.FloatValue":  marshalField("value"),
+		"google.protobuf.Int64Value":  marshalField("value"),
+		"google.protobuf.UInt64Value": marshalField("value"),
+		"google.protobuf.Int32Value":  marshalField("value"),
+		"google.protobuf.UInt32Value": marshalField("value"),
+		"google.protobuf.BoolValue":   marshalField("value"),
+		"google.protobuf.StringValue": marshalField("value"),
+		"google.protobuf.BytesValue":  marshalField("value"),
+	}
+}
+
+func field(m pref.Message, name pref.Name) pref.FieldDescriptor {
+	return m.Descriptor().Fields().ByName(name)
+}
+
+// marshalField marshals a message as the value of its field name, as for
+// the wrappers, Struct and ListValue.
+func marshalField(name pref.Name) func(w *jsonWriter, m pref.Message) error {
+	return func(w *jsonWriter, m pref.Message) error {
+		fd := field(m, name)
+		return w.marshalValue(m.Get(fd), fd)
+	}
+}
+
+func marshalEmpty(w *jsonWriter, m pref.Message) error {
+	w.write("{}")
+	return nil
+}
+
+// marshalKnownValue marshals a google.protobuf.Value as the JSON value it
+// holds.
+func marshalKnownValue(w *jsonWriter, m pref.Message) error {
+	fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("kind"))
+	if fd == nil || fd.Name() == "null_value" {
+		w.write("null")
+		return nil
+	}
+	return w.marshalSingular(m.Get(fd), fd)
+}
+
+// marshalTimestamp marshals a google.protobuf.Timestamp as an RFC 3339 date
+// in UTC, with 0, 3, 6 or 9 fractional digits.
+func marshalTimestamp(w *jsonWriter, m pref.Message) error {
+	seconds, nanos := m.Get(field(m, "seconds")).Int(), m.Get(field(m, "nanos")).Int()
+	if nanos < 0 || nanos >= 1e9 {
+		return fmt.Errorf("%s: nanos out of range: %d", m.Descriptor().FullName(), nanos)
+	}
+	t := time.Unix(seconds, nanos).UTC()
+	w.write(`"` + t.Format("2006-01-02T15:04:05") + fractional(nanos) + `Z"`)
+	return nil
+}
+
+// marshalDuration marshals a google.protobuf.Duration as seconds with a "s"
+// suffix, e.g. "1.500s".
+func marshalDuration(w *jsonWriter, m pref.Message) error {
+	seconds, nanos := m.Get(field(m, "seconds")).Int(), m.Get(field(m, "nanos")).Int()
+	if nanos <= -1e9 || nanos >= 1e9 || seconds > 0 && nanos < 0 || seconds < 0 && nanos > 0 {
+		return fmt.Errorf("%s: invalid duration %ds %dns", m.Descriptor().FullName(), seconds, nanos)
+	}
+	sign := ""
+	if seconds < 0 || nanos < 0 {
+		sign, seconds, nanos = "-", -seconds, -nanos
+	}
+	w.write(fmt.Sprintf(`"%s%d%ss"`, sign, seconds, fractional(nanos)))
+	return nil
+}
+
+// fractional formats nanoseconds as a fraction of a second with 0, 3, 6 or
+// 9 digits.
+func fractional(nanos int64) string {
+	if nanos == 0 {
+		return ""
+	}
+	s := fmt.Sprintf(".%09d", nanos)
+	for len(s) > 4 && strings.HasSuffix(s, "000") {
+		s = s[:len(s)-3]
+	}
+	return s
+}
+
+// marshalFieldMask marshals a google.protobuf.FieldMask as its comma
+// separated paths in lowerCamelCase.
+func marshalFieldMask(w *jsonWriter, m pref.Message) error {
+	list := m.Get(field(m, "paths")).List()
+	paths := make([]string, list.Len())
+	for i := range paths {
+		paths[i] = lowerCamelCase(list.Get(i).String())
+	}
+	return w.writeString(strings.Join(paths, ","))
+}
+
+func lowerCamelCase(s string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range s {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && 'a' <= r && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
+	}
+	return b.String()
+}
+
+// marshalAny marshals a google.protobuf.Any as the message it holds with an
+// "@type" field, or its JSON mapping under "value" for well-known types.
+// Messages of types the Resolver of the options does not know are written
+// as their base64 encoding under "value".
+func marshalAny(w *jsonWriter, m pref.Message) error {
+	url := m.Get(field(m, "type_url")).String()
+	value := m.Get(field(m, "value")).Bytes()
+	w.write(`{"@type":`)
+	if err := w.writeString(url); err != nil {
+		return err
+	}
+
+	var resolver TypeResolver = protoregistry.GlobalTypes
+	if w.opts.Resolver != nil {
+		resolver = w.opts.Resolver
+	}
+	mt, err := resolver.FindMessageByURL(url)
+	if err != nil {
+		w.write(`,"value":"` + base64.StdEncoding.EncodeToString(value) + `"}`)
+		return nil
+	}
+	inner := mt.New()
+	if err := proto.Unmarshal(value, inner.Interface()); err != nil {
+		return fmt.Errorf("%s: %v", url, err)
+	}
+
+	if _, ok := wellKnownTypes[inner.Descriptor().FullName()]; ok {
+		w.write(`,"value":`)
+		if err := w.marshalMessage(inner); err != nil {
+			return err
+		}
+		w.write(`}`)
+		return nil
+	}
+
+	// Splice the fields of the message after "@type", turning its opening
+	// brace into a comma unless it has none.
+	start := len(w.buf)
+	if err := w.marshalFields(inner); err != nil {
+		return err
+	}
+	if len(w.buf) == start+2 {
+		w.buf = w.buf[:start+1]
+		w.buf[start] = '}'
+	} else {
+		w.buf[start] = ','
+	}
+	return nil
+}
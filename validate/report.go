@@ -0,0 +1,23 @@
+package validate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteText writes anomalies as a table with one row per anomaly.
+func WriteText(w io.Writer, anomalies []*Anomaly, records int) error {
+	if len(anomalies) == 0 {
+		_, err := fmt.Fprintf(w, "no anomalies found in %d records\n", records)
+		return err
+	}
+	fmt.Fprintf(w, "%d anomalies found in %d records\n\n", len(anomalies), records)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "feature\tanomaly\trecords\tdescription\texamples")
+	for _, a := range anomalies {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", a.Feature, a.Type, a.Records, a.Description, strings.Join(a.Examples, " "))
+	}
+	return tw.Flush()
+}
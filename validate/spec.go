@@ -0,0 +1,207 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/schema"
+	"github.com/emla2805/tfr/spec"
+	"google.golang.org/protobuf/proto"
+)
+
+// Parse error types, the errors tf.io.parse_example and
+// tf.io.parse_sequence_example raise.
+const (
+	RequiredFeature = "missing required feature"
+	DtypeMismatch   = "dtype mismatch"
+	WrongLength     = "wrong number of values"
+	SparseMismatch  = "sparse length mismatch"
+	SparseIndex     = "sparse index out of range"
+)
+
+// kinds maps dtypes to the feature kind they are parsed from.
+var kinds = map[string]string{
+	spec.DtypeInt64:   schema.KindInt64,
+	spec.DtypeFloat32: schema.KindFloat,
+	spec.DtypeString:  schema.KindBytes,
+}
+
+// SpecChecker finds the records a feature spec fails to parse, following
+// the rules of TensorFlow's parsing ops.
+type SpecChecker struct {
+	report
+	context, sequence map[string]*spec.Feature
+	// specs holds the feature spec of each anomaly, to describe it.
+	specs map[anomalyKey]*spec.Feature
+}
+
+// NewSpecChecker returns a SpecChecker parsing records with s. Examples are
+// parsed as SequenceExamples without feature lists, and the context of
+// SequenceExamples as Examples, since they share their wire format.
+func NewSpecChecker(s *spec.Spec) *SpecChecker {
+	context := s.Features
+	if context == nil {
+		context = s.ContextFeatures
+	}
+	return &SpecChecker{
+		report:   newReport(),
+		context:  context,
+		sequence: s.SequenceFeatures,
+		specs:    map[anomalyKey]*spec.Feature{},
+	}
+}
+
+// Check parses a protobuf.Example or protobuf.SequenceExample. ref
+// identifies the record in anomaly examples.
+func (c *SpecChecker) Check(m proto.Message, ref string) {
+	c.next()
+	var fs *protobuf.Features
+	var fl *protobuf.FeatureLists
+	switch m := m.(type) {
+	case *protobuf.Example:
+		fs = m.GetFeatures()
+	case *protobuf.SequenceExample:
+		fs = m.GetContext()
+		fl = m.GetFeatureLists()
+	}
+	for _, name := range sortedNames(c.context) {
+		c.checkFeature(name, c.context[name], fs, ref)
+	}
+	for _, name := range sortedNames(c.sequence) {
+		c.checkFeatureList(name, c.sequence[name], fl, ref)
+	}
+}
+
+func (c *SpecChecker) fail(name string, f *spec.Feature, typ, ref, value string) {
+	c.specs[anomalyKey{name, typ}] = f
+	c.add(name, typ, ref, value)
+}
+
+func (c *SpecChecker) checkFeature(name string, f *spec.Feature, fs *protobuf.Features, ref string) {
+	if f.Type == spec.Sparse {
+		c.checkSparse(name, f, fs, ref)
+		return
+	}
+	// A feature without a kind set counts as missing.
+	value := fs.GetFeature()[name]
+	if value.GetKind() == nil {
+		if f.Type == spec.FixedLen && f.Default == nil {
+			c.fail(name, f, RequiredFeature, ref, "")
+		}
+		return
+	}
+	kind, count := schema.Kind(value)
+	if kind != kinds[f.Dtype] {
+		c.fail(name, f, DtypeMismatch, ref, kind)
+		return
+	}
+	switch f.Type {
+	case spec.FixedLen:
+		if count != f.Elements() {
+			c.fail(name, f, WrongLength, ref, strconv.Itoa(count))
+		}
+	case spec.FixedLenSequence:
+		if n := f.Elements(); n == 0 && count > 0 || n > 0 && count%n != 0 {
+			c.fail(name, f, WrongLength, ref, strconv.Itoa(count))
+		}
+	}
+}
+
+func (c *SpecChecker) checkSparse(name string, f *spec.Feature, fs *protobuf.Features, ref string) {
+	values := 0
+	if value := fs.GetFeature()[f.ValueKey]; value.GetKind() != nil {
+		kind, count := schema.Kind(value)
+		if kind != kinds[f.Dtype] {
+			c.fail(name, f, DtypeMismatch, ref, f.ValueKey+": "+kind)
+			return
+		}
+		values = count
+	}
+	for i, key := range f.IndexKey {
+		index := fs.GetFeature()[key]
+		if index.GetKind() == nil {
+			if values > 0 {
+				c.fail(name, f, SparseMismatch, ref, fmt.Sprintf("%s: 0", key))
+			}
+			continue
+		}
+		if kind, _ := schema.Kind(index); kind != schema.KindInt64 {
+			c.fail(name, f, DtypeMismatch, ref, key+": "+kind)
+			continue
+		}
+		indices := index.GetInt64List().GetValue()
+		if len(indices) != values {
+			c.fail(name, f, SparseMismatch, ref, fmt.Sprintf("%s: %d", key, len(indices)))
+		}
+		for _, x := range indices {
+			if x < 0 || x >= int64(f.Size[i]) {
+				c.fail(name, f, SparseIndex, ref, fmt.Sprintf("%s: %d", key, x))
+			}
+		}
+	}
+}
+
+func (c *SpecChecker) checkFeatureList(name string, f *spec.Feature, fl *protobuf.FeatureLists, ref string) {
+	list, ok := fl.GetFeatureList()[name]
+	if !ok {
+		if f.Type == spec.FixedLenSequence && !f.AllowMissing {
+			c.fail(name, f, RequiredFeature, ref, "")
+		}
+		return
+	}
+	for _, step := range list.GetFeature() {
+		kind, count := schema.Kind(step)
+		if step.GetKind() != nil && kind != kinds[f.Dtype] {
+			c.fail(name, f, DtypeMismatch, ref, kind)
+			continue
+		}
+		if f.Type == spec.FixedLenSequence && count != f.Elements() {
+			c.fail(name, f, WrongLength, ref, strconv.Itoa(count))
+		}
+	}
+}
+
+// Anomalies returns the parse errors found, sorted by feature and type.
+func (c *SpecChecker) Anomalies() []*Anomaly {
+	return c.sorted(c.describe)
+}
+
+// describe explains a parse error in terms of the feature spec.
+func (c *SpecChecker) describe(key anomalyKey) string {
+	f := c.specs[key]
+	got := gotValues(c.anomalies[key])
+
+	switch key.typ {
+	case RequiredFeature:
+		return "feature is missing, required by " + f.String()
+	case DtypeMismatch:
+		if f.Type == spec.Sparse {
+			return fmt.Sprintf("%s expects int64 indices and %s values%s", f.String(), kinds[f.Dtype], got)
+		}
+		return fmt.Sprintf("%s expects %s%s", f.String(), kinds[f.Dtype], got)
+	case WrongLength:
+		if f.Type == spec.FixedLenSequence && c.sequence[key.feature] == f {
+			return fmt.Sprintf("%s expects %d values per step%s", f.String(), f.Elements(), got)
+		}
+		if f.Type == spec.FixedLenSequence {
+			return fmt.Sprintf("%s expects a multiple of %d values%s", f.String(), f.Elements(), got)
+		}
+		return fmt.Sprintf("%s expects %d values%s", f.String(), f.Elements(), got)
+	case SparseMismatch:
+		return fmt.Sprintf("index lengths differ from the number of %s values%s", f.ValueKey, got)
+	case SparseIndex:
+		return fmt.Sprintf("indices must be within size %v%s", f.Size, got)
+	}
+	return key.typ
+}
+
+func sortedNames(features map[string]*spec.Feature) []string {
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,93 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/spec"
+)
+
+const specJSON = `{
+  "features": {
+    "age": {"type": "FixedLenFeature", "dtype": "int64"},
+    "embedding": {"type": "FixedLenFeature", "dtype": "float32", "shape": [2], "default_value": [0, 0]},
+    "movie": {"type": "VarLenFeature", "dtype": "string"},
+    "rating": {"type": "SparseFeature", "dtype": "float32", "index_key": ["movie_id"], "value_key": "movie_rating", "size": [100]}
+  }
+}`
+
+func TestSpecChecker(t *testing.T) {
+	s, err := spec.Read(strings.NewReader(specJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewSpecChecker(s)
+	c.Check(protobuf.NewExample().
+		Int64("age", 29).
+		Strings("movie", "Heat").
+		Int64("movie_id", 3, 7).
+		Float("movie_rating", 9, 7.5).
+		Build(), "0")
+	c.Check(protobuf.NewExample().
+		Int64("age", 29, 30).
+		Float("embedding", 1, 2, 3).
+		Int64("movie", 1).
+		Int64("movie_id", 3, 700).
+		Float("movie_rating", 9).
+		Build(), "1")
+	// A feature without a kind is missing.
+	c.Check(&protobuf.Example{Features: &protobuf.Features{Feature: map[string]*protobuf.Feature{
+		"age":       {},
+		"embedding": protobuf.Int64Feature(1, 2),
+	}}}, "2")
+
+	var got []string
+	for _, a := range c.Anomalies() {
+		got = append(got, a.Feature+": "+a.Type+": "+a.Description+": "+strings.Join(a.Examples, ","))
+	}
+	want := []string{
+		"age: missing required feature: feature is missing, required by tf.io.FixedLenFeature([], tf.int64): 2",
+		"age: wrong number of values: tf.io.FixedLenFeature([], tf.int64) expects 1 values, got 2: 1",
+		"embedding: dtype mismatch: tf.io.FixedLenFeature([2], tf.float32, default_value=[0, 0]) expects float, got int64: 2",
+		"embedding: wrong number of values: tf.io.FixedLenFeature([2], tf.float32, default_value=[0, 0]) expects 2 values, got 3: 1",
+		"movie: dtype mismatch: tf.io.VarLenFeature(tf.string) expects bytes, got int64: 1",
+		`rating: sparse index out of range: indices must be within size [100], got movie_id: 700: 1`,
+		"rating: sparse length mismatch: index lengths differ from the number of movie_rating values, got movie_id: 2: 1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSpecCheckerSequence(t *testing.T) {
+	s, err := spec.Read(strings.NewReader(`{
+  "context_features": {"user": {"type": "FixedLenFeature", "dtype": "string", "default_value": ""}},
+  "sequence_features": {
+    "ratings": {"type": "FixedLenSequenceFeature", "dtype": "float32", "shape": [2]},
+    "movies": {"type": "FixedLenSequenceFeature", "dtype": "string", "allow_missing": true}
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewSpecChecker(s)
+	c.Check(protobuf.NewSequenceExample().
+		FloatSteps("ratings", []float32{1, 2}, []float32{3}).
+		Build(), "0")
+	c.Check(protobuf.NewExample().Strings("user", "u1").Build(), "1")
+
+	var got []string
+	for _, a := range c.Anomalies() {
+		got = append(got, a.Feature+": "+a.Type+": "+strings.Join(a.Examples, ","))
+	}
+	want := []string{
+		"ratings: missing required feature: 1",
+		"ratings: wrong number of values: 0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,343 @@
+// Package validate checks records against a schema and reports anomalies the
+// way TensorFlow Data Validation does: one anomaly per feature and kind of
+// problem, with the number of records affected and a few examples.
+package validate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/schema"
+	"google.golang.org/protobuf/proto"
+)
+
+// Anomaly types.
+const (
+	MissingFeature    = "missing feature"
+	UnexpectedFeature = "unexpected feature"
+	WrongKind         = "wrong kind"
+	ValueCount        = "value count out of range"
+	StepCount         = "steps out of range"
+	OutOfDomain       = "value out of domain"
+	UnexpectedString  = "unexpected string value"
+	LowPresence       = "low presence"
+)
+
+// MaxExamples is the number of example records and values kept per anomaly.
+const MaxExamples = 5
+
+// Anomaly is a problem found with a feature in one or more records.
+type Anomaly struct {
+	Feature     string `json:"feature"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// Records is the number of records with the anomaly.
+	Records int `json:"records"`
+	// Examples refer to the first records with the anomaly.
+	Examples []string `json:"examples,omitempty"`
+	// Values holds the first offending values for domain anomalies.
+	Values []string `json:"values,omitempty"`
+}
+
+type anomalyKey struct {
+	feature, typ string
+}
+
+// report collects anomalies, counting each record at most once per anomaly.
+type report struct {
+	records   int
+	anomalies map[anomalyKey]*Anomaly
+	// seen holds the anomalies already counted for the current record.
+	seen map[anomalyKey]bool
+}
+
+func newReport() report {
+	return report{anomalies: map[anomalyKey]*Anomaly{}}
+}
+
+// next starts a new record.
+func (r *report) next() {
+	r.records++
+	r.seen = map[anomalyKey]bool{}
+}
+
+// add records an anomaly of the current record. value is an offending
+// value to show as an example, if any.
+func (r *report) add(feature, typ, ref, value string) {
+	key := anomalyKey{feature, typ}
+	a, ok := r.anomalies[key]
+	if !ok {
+		a = &Anomaly{Feature: feature, Type: typ}
+		r.anomalies[key] = a
+	}
+	if value != "" && len(a.Values) < MaxExamples && !contains(a.Values, value) {
+		a.Values = append(a.Values, value)
+	}
+	if r.seen[key] {
+		return
+	}
+	r.seen[key] = true
+	a.Records++
+	if len(a.Examples) < MaxExamples {
+		a.Examples = append(a.Examples, ref)
+	}
+}
+
+// Records returns the number of records checked.
+func (r *report) Records() int {
+	return r.records
+}
+
+// sorted returns the anomalies sorted by feature and type, describing those
+// without a description yet with describe.
+func (r *report) sorted(describe func(anomalyKey) string) []*Anomaly {
+	anomalies := make([]*Anomaly, 0, len(r.anomalies))
+	for key, a := range r.anomalies {
+		if a.Description == "" {
+			a.Description = describe(key)
+		}
+		anomalies = append(anomalies, a)
+	}
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].Feature != anomalies[j].Feature {
+			return anomalies[i].Feature < anomalies[j].Feature
+		}
+		return anomalies[i].Type < anomalies[j].Type
+	})
+	return anomalies
+}
+
+// gotValues lists the offending values of an anomaly, if any.
+func gotValues(a *Anomaly) string {
+	if len(a.Values) == 0 {
+		return ""
+	}
+	return ", got " + strings.Join(a.Values, ", ")
+}
+
+// Validator validates records against a schema.
+type Validator struct {
+	report
+	features     map[string]*schema.Feature
+	featureLists map[string]*schema.Feature
+	vocabularies map[*schema.Feature]map[string]bool
+	present      map[*schema.Feature]int
+}
+
+// New returns a Validator checking records against s.
+func New(s *schema.Schema) *Validator {
+	v := &Validator{
+		report:       newReport(),
+		features:     map[string]*schema.Feature{},
+		featureLists: map[string]*schema.Feature{},
+		vocabularies: map[*schema.Feature]map[string]bool{},
+		present:      map[*schema.Feature]int{},
+	}
+	for _, f := range s.Features {
+		v.features[f.Name] = f
+	}
+	for _, f := range s.FeatureLists {
+		v.featureLists[f.Name] = f
+	}
+	for _, f := range append(s.Features, s.FeatureLists...) {
+		if len(f.Vocabulary) > 0 {
+			vocabulary := map[string]bool{}
+			for _, value := range f.Vocabulary {
+				vocabulary[value] = true
+			}
+			v.vocabularies[f] = vocabulary
+		}
+	}
+	return v
+}
+
+// Validate checks a protobuf.Example or protobuf.SequenceExample. ref
+// identifies the record in anomaly examples.
+func (v *Validator) Validate(m proto.Message, ref string) {
+	v.next()
+	switch m := m.(type) {
+	case *protobuf.Example:
+		v.validateFeatures(m.GetFeatures(), ref)
+		v.validateFeatureLists(nil, ref)
+	case *protobuf.SequenceExample:
+		v.validateFeatures(m.GetContext(), ref)
+		v.validateFeatureLists(m.GetFeatureLists(), ref)
+	}
+}
+
+func (v *Validator) validateFeatures(fs *protobuf.Features, ref string) {
+	for name, f := range v.features {
+		value, ok := fs.GetFeature()[name]
+		if !ok {
+			if f.Presence >= 1 {
+				v.add(name, MissingFeature, ref, "")
+			}
+			continue
+		}
+		v.present[f]++
+		v.validateValue(f, value, ref)
+	}
+	for name := range fs.GetFeature() {
+		if _, ok := v.features[name]; !ok {
+			v.add(name, UnexpectedFeature, ref, "")
+		}
+	}
+}
+
+func (v *Validator) validateFeatureLists(fl *protobuf.FeatureLists, ref string) {
+	for name, f := range v.featureLists {
+		list, ok := fl.GetFeatureList()[name]
+		if !ok {
+			if f.Presence >= 1 {
+				v.add(name, MissingFeature, ref, "")
+			}
+			continue
+		}
+		v.present[f]++
+		if steps := len(list.GetFeature()); steps < f.MinSteps || steps > f.MaxSteps {
+			v.add(name, StepCount, ref, strconv.Itoa(steps))
+		}
+		for _, value := range list.GetFeature() {
+			v.validateValue(f, value, ref)
+		}
+	}
+	for name := range fl.GetFeatureList() {
+		if _, ok := v.featureLists[name]; !ok {
+			v.add(name, UnexpectedFeature, ref, "")
+		}
+	}
+}
+
+func (v *Validator) validateValue(f *schema.Feature, value *protobuf.Feature, ref string) {
+	kind, count := schema.Kind(value)
+	if f.Kind != "" && kind != f.Kind {
+		v.add(f.Name, WrongKind, ref, kind)
+		return
+	}
+	if count < f.MinCount || count > f.MaxCount {
+		v.add(f.Name, ValueCount, ref, strconv.Itoa(count))
+	}
+
+	if d := f.IntDomain; d != nil {
+		for _, x := range value.GetInt64List().GetValue() {
+			if d.Min != nil && x < *d.Min || d.Max != nil && x > *d.Max {
+				v.add(f.Name, OutOfDomain, ref, strconv.FormatInt(x, 10))
+			}
+		}
+	}
+	if d := f.FloatDomain; d != nil {
+		for _, x := range value.GetFloatList().GetValue() {
+			x := float64(x)
+			if math.IsNaN(x) || d.Min != nil && x < *d.Min || d.Max != nil && x > *d.Max {
+				v.add(f.Name, OutOfDomain, ref, strconv.FormatFloat(x, 'g', -1, 32))
+			}
+		}
+	}
+	if vocabulary, ok := v.vocabularies[f]; ok {
+		for _, b := range value.GetBytesList().GetValue() {
+			if !vocabulary[string(b)] {
+				v.add(f.Name, UnexpectedString, ref, strconv.Quote(string(b)))
+			}
+		}
+	}
+}
+
+// Anomalies returns the anomalies found, sorted by feature and type,
+// including features present in fewer records than the schema requires.
+func (v *Validator) Anomalies() []*Anomaly {
+	for _, f := range append(v.sortedFeatures(v.features), v.sortedFeatures(v.featureLists)...) {
+		if f.Presence <= 0 || f.Presence >= 1 || v.records == 0 {
+			continue
+		}
+		if fraction := float64(v.present[f]) / float64(v.records); fraction < f.Presence {
+			v.anomalies[anomalyKey{f.Name, LowPresence}] = &Anomaly{
+				Feature: f.Name,
+				Type:    LowPresence,
+				Records: v.records - v.present[f],
+				Description: fmt.Sprintf("present in %.1f%% of records, expected at least %.1f%%",
+					100*fraction, 100*f.Presence),
+			}
+		}
+	}
+	return v.sorted(v.describe)
+}
+
+func (v *Validator) sortedFeatures(features map[string]*schema.Feature) []*schema.Feature {
+	list := make([]*schema.Feature, 0, len(features))
+	for _, f := range features {
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// describe explains an anomaly in terms of the schema.
+func (v *Validator) describe(key anomalyKey) string {
+	f, ok := v.features[key.feature]
+	if !ok {
+		f = v.featureLists[key.feature]
+	}
+	got := gotValues(v.anomalies[key])
+
+	switch key.typ {
+	case MissingFeature:
+		return "required feature is missing"
+	case UnexpectedFeature:
+		return "feature is not in the schema"
+	case WrongKind:
+		return "expected " + f.Kind + got
+	case ValueCount:
+		return "expected " + countRange(f.MinCount, f.MaxCount) + " values" + got
+	case StepCount:
+		return "expected " + countRange(f.MinSteps, f.MaxSteps) + " steps" + got
+	case OutOfDomain:
+		return "expected values in " + domainRange(f) + got
+	case UnexpectedString:
+		return fmt.Sprintf("expected one of %d values%s", len(f.Vocabulary), got)
+	}
+	return key.typ
+}
+
+func countRange(min, max int) string {
+	switch {
+	case min == max:
+		return strconv.Itoa(min)
+	case max == math.MaxInt32:
+		return "at least " + strconv.Itoa(min)
+	}
+	return fmt.Sprintf("%d to %d", min, max)
+}
+
+func domainRange(f *schema.Feature) string {
+	min, max := "-inf", "inf"
+	if d := f.IntDomain; d != nil {
+		if d.Min != nil {
+			min = strconv.FormatInt(*d.Min, 10)
+		}
+		if d.Max != nil {
+			max = strconv.FormatInt(*d.Max, 10)
+		}
+	}
+	if d := f.FloatDomain; d != nil {
+		if d.Min != nil {
+			min = strconv.FormatFloat(*d.Min, 'g', -1, 64)
+		}
+		if d.Max != nil {
+			max = strconv.FormatFloat(*d.Max, 'g', -1, 64)
+		}
+	}
+	return "[" + min + ", " + max + "]"
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
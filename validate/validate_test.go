@@ -0,0 +1,78 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	protobuf "github.com/emla2805/tfr/protobuf"
+	"github.com/emla2805/tfr/schema"
+)
+
+const schemaPbtxt = `
+# Movie ratings.
+string_domain {
+  name: "movies"
+  value: "The Shawshank Redemption"
+  value: "Fight " "Club"
+}
+feature {
+  name: "age"
+  type: INT
+  presence { min_fraction: 1.0 min_count: 1 }
+  shape { dim { size: 1 } }
+  int_domain { min: 18 max: 99 }
+}
+feature <
+  name: 'movie'
+  type: BYTES
+  domain: "movies"
+  value_count: { min: 1 max: 2 }
+>
+feature {
+  name: "rating"
+  type: FLOAT
+  presence { min_fraction: 0.9 }
+  float_domain { min: 0 max: 10 }
+}
+`
+
+func TestValidate(t *testing.T) {
+	s, err := schema.Read(strings.NewReader(schemaPbtxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := New(s)
+	v.Validate(protobuf.NewExample().
+		Int64("age", 29).
+		Strings("movie", "The Shawshank Redemption", "Fight Club").
+		Float("rating", 9.5).
+		Build(), "0")
+	v.Validate(protobuf.NewExample().
+		Int64("age", 12, 13).
+		Strings("movie", "Heat").
+		Float("rating", 11).
+		Int64("extra", 1).
+		Build(), "1")
+	v.Validate(protobuf.NewExample().
+		Int64("movie", 1).
+		Build(), "2")
+
+	var got []string
+	for _, a := range v.Anomalies() {
+		got = append(got, a.Feature+": "+a.Type+": "+a.Description+": "+strings.Join(a.Examples, ","))
+	}
+	want := []string{
+		"age: missing feature: required feature is missing: 2",
+		"age: value count out of range: expected 1 values, got 2: 1",
+		"age: value out of domain: expected values in [18, 99], got 12, 13: 1",
+		"extra: unexpected feature: feature is not in the schema: 1",
+		"movie: unexpected string value: expected one of 2 values, got \"Heat\": 1",
+		"movie: wrong kind: expected bytes, got int64: 2",
+		"rating: low presence: present in 66.7% of records, expected at least 90.0%: ",
+		"rating: value out of domain: expected values in [0, 10], got 11: 1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}